@@ -0,0 +1,145 @@
+// Package amount converts the amounts of assets between their base unit and decimal strings, using the decimals
+// of the assets, e.g. "1.5" BNB is 1500000000000000000.
+package amount
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+var (
+	// ErrInvalidAmount is returned for a string which is not a non negative decimal number
+	ErrInvalidAmount = errors.New("invalid amount")
+	// ErrTooPrecise is returned for an amount with more decimals than its asset
+	ErrTooPrecise = errors.New("amount has more decimals than the asset")
+)
+
+// Parse converts a decimal string, e.g. "1.5", to an amount in base unit with the decimals of the asset
+func Parse(value string, decimals uint32) (*big.Int, error) {
+	value = strings.TrimSpace(value)
+	whole, fraction, _ := strings.Cut(value, ".")
+	if whole == "" && fraction == "" || !isDigits(whole) || !isDigits(fraction) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > int(decimals) {
+		return nil, fmt.Errorf("%w: %q has %d decimals, the asset has %d", ErrTooPrecise, value, len(fraction), decimals)
+	}
+
+	digits := whole + fraction + strings.Repeat("0", int(decimals)-len(fraction))
+	result, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+	return result, nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Format converts an amount in base unit to a decimal string without trailing zeros, e.g. "1.5"
+func Format(value *big.Int, decimals uint32) string {
+	if value == nil {
+		return "0"
+	}
+	sign := ""
+	if value.Sign() < 0 {
+		sign = "-"
+	}
+	digits := new(big.Int).Abs(value).String()
+	if len(digits) <= int(decimals) {
+		digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+	}
+	whole, fraction := digits[:len(digits)-int(decimals)], strings.TrimRight(digits[len(digits)-int(decimals):], "0")
+	if fraction == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + fraction
+}
+
+// FormatString is like Format for an amount in base unit given as a string, like the balances of
+// types.AccountAsset or the fees of types.GasFee
+func FormatString(value string, decimals uint32) (string, error) {
+	v, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+	return Format(v, decimals), nil
+}
+
+// New returns the amount of the asset in base unit
+func New(asset *types.Asset, value *big.Int) *types.Amount {
+	return &types.Amount{Asset: asset, Value: value}
+}
+
+// ParseAmount parses a decimal string of the asset, e.g. "1.5" BNB
+func ParseAmount(asset *types.Asset, value string) (*types.Amount, error) {
+	if asset == nil {
+		return nil, types.ErrAmountWithoutAsset
+	}
+	v, err := Parse(value, asset.Decimals)
+	if err != nil {
+		return nil, err
+	}
+	return New(asset, v), nil
+}
+
+// String formats the amount with the symbol of its asset, e.g. "1.5 BNB". An amount without asset is formatted in
+// base unit without symbol.
+func String(amount *types.Amount) string {
+	if amount.Asset == nil {
+		return Format(amount.Value, 0)
+	}
+	return Format(amount.Value, amount.Asset.Decimals) + " " + amount.Asset.Symbol
+}
+
+// AssetResolver looks up the assets, the l2 client is one
+type AssetResolver interface {
+	GetAssetById(id uint32) (*types.Asset, error)
+	GetAssetBySymbol(symbol string) (*types.Asset, error)
+}
+
+// ResolveAsset returns the asset of a symbol, e.g. "BNB", or of an asset id, e.g. "0"
+func ResolveAsset(resolver AssetResolver, asset string) (*types.Asset, error) {
+	if id, err := strconv.ParseUint(asset, 10, 32); err == nil {
+		return resolver.GetAssetById(uint32(id))
+	}
+	return resolver.GetAssetBySymbol(asset)
+}
+
+// Resolve parses a decimal amount followed by the symbol or the id of its asset, e.g. "1.5 BNB"
+func Resolve(resolver AssetResolver, amount string) (*types.Amount, error) {
+	fields := strings.Fields(amount)
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("%w: %q, expected an amount and an asset like \"1.5 BNB\"", ErrInvalidAmount, amount)
+	}
+	asset, err := ResolveAsset(resolver, fields[1])
+	if err != nil {
+		return nil, err
+	}
+	return ParseAmount(asset, fields[0])
+}
+
+// FormatBalance formats the balance of an asset of an account with the symbol of the asset, e.g. "1.5 BNB"
+func FormatBalance(resolver AssetResolver, balance *types.AccountAsset) (string, error) {
+	asset, err := resolver.GetAssetById(balance.Id)
+	if err != nil {
+		return "", err
+	}
+	value, err := FormatString(balance.Balance, asset.Decimals)
+	if err != nil {
+		return "", err
+	}
+	return value + " " + asset.Symbol, nil
+}
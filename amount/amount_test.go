@@ -0,0 +1,119 @@
+package amount
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+var (
+	bnb = &types.Asset{Id: 0, Symbol: "BNB", Decimals: 18}
+	usd = &types.Asset{Id: 1, Symbol: "USD", Decimals: 2}
+)
+
+type assets []*types.Asset
+
+func (a assets) GetAssetById(id uint32) (*types.Asset, error) {
+	for _, asset := range a {
+		if asset.Id == id {
+			return asset, nil
+		}
+	}
+	return nil, errors.New("asset not found")
+}
+
+func (a assets) GetAssetBySymbol(symbol string) (*types.Asset, error) {
+	for _, asset := range a {
+		if asset.Symbol == symbol {
+			return asset, nil
+		}
+	}
+	return nil, errors.New("asset not found")
+}
+
+func TestParse(t *testing.T) {
+	for value, expected := range map[string]string{
+		"1.5":     "150",
+		"1":       "100",
+		"0.01":    "1",
+		".5":      "50",
+		"2.":      "200",
+		"1.500":   "150",
+		" 12.34 ": "1234",
+		"0":       "0",
+	} {
+		result, err := Parse(value, usd.Decimals)
+		if assert.NoError(t, err, value) {
+			assert.Equal(t, expected, result.String(), value)
+		}
+	}
+
+	result, err := Parse("1.5", bnb.Decimals)
+	require.NoError(t, err)
+	assert.Equal(t, "1500000000000000000", result.String())
+
+	for _, value := range []string{"", ".", "-1", "1.2.3", "1,5", "1e3", "abc"} {
+		_, err := Parse(value, usd.Decimals)
+		assert.ErrorIs(t, err, ErrInvalidAmount, value)
+	}
+	_, err = Parse("0.001", usd.Decimals)
+	assert.ErrorIs(t, err, ErrTooPrecise)
+	_, err = Parse("1.1", 0)
+	assert.ErrorIs(t, err, ErrTooPrecise)
+}
+
+func TestFormat(t *testing.T) {
+	for value, expected := range map[int64]string{
+		150:  "1.5",
+		100:  "1",
+		1:    "0.01",
+		0:    "0",
+		1234: "12.34",
+		-150: "-1.5",
+	} {
+		assert.Equal(t, expected, Format(big.NewInt(value), usd.Decimals))
+	}
+	assert.Equal(t, "1.5", Format(big.NewInt(15e17), bnb.Decimals))
+	assert.Equal(t, "7", Format(big.NewInt(7), 0))
+	assert.Equal(t, "0", Format(nil, 18))
+
+	formatted, err := FormatString("1000000000000000", bnb.Decimals)
+	require.NoError(t, err)
+	assert.Equal(t, "0.001", formatted)
+	_, err = FormatString("1.5", bnb.Decimals)
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+}
+
+func TestResolve(t *testing.T) {
+	resolver := assets{bnb, usd}
+
+	amount, err := Resolve(resolver, "1.5 BNB")
+	require.NoError(t, err)
+	assert.Equal(t, bnb, amount.Asset)
+	assert.Equal(t, big.NewInt(15e17), amount.Value)
+	assert.Equal(t, "1.5 BNB", String(amount))
+	assert.Equal(t, "1500000000000000000", String(&types.Amount{Value: amount.Value}))
+	_, err = ParseAmount(nil, "1.5")
+	assert.ErrorIs(t, err, types.ErrAmountWithoutAsset)
+
+	amount, err = Resolve(resolver, "2.25 1")
+	require.NoError(t, err)
+	assert.Equal(t, usd, amount.Asset)
+	assert.Equal(t, big.NewInt(225), amount.Value)
+
+	_, err = Resolve(resolver, "2.255 USD")
+	assert.ErrorIs(t, err, ErrTooPrecise)
+	_, err = Resolve(resolver, "1.5")
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+	_, err = Resolve(resolver, "1.5 ETH")
+	assert.EqualError(t, err, "asset not found")
+
+	balance, err := FormatBalance(resolver, &types.AccountAsset{Id: 1, Balance: "12345"})
+	require.NoError(t, err)
+	assert.Equal(t, "123.45 USD", balance)
+}
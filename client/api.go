@@ -1,7 +1,10 @@
 package client
 
 import (
+	"context"
+	"encoding/json"
 	"math/big"
+	"net/url"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -13,7 +16,12 @@ import (
 
 type ZkBNBClient interface {
 	ZkBNBQuerier
+	ZkBNBQuerierWithContext
+	ZkBNBIterators
+	ZkBNBWatcher
+	ZkBNBVerifier
 	ZkBNBTxSender
+	ZkBNBTxSenderWithContext
 }
 
 type getTxOption struct {
@@ -23,6 +31,14 @@ type getTxOption struct {
 
 type GetTxOptionFunc func(*getTxOption)
 
+// setTypes adds the json encoded tx types filter to the query params
+func (o *getTxOption) setTypes(params url.Values) {
+	if len(o.Types) > 0 {
+		txTypes, _ := json.Marshal(o.Types)
+		params.Set("types", string(txTypes))
+	}
+}
+
 func GetTxWithTypes(txTypes []int64) GetTxOptionFunc {
 	return func(o *getTxOption) {
 		o.Types = txTypes
@@ -122,6 +138,149 @@ type ZkBNBQuerier interface {
 	GetNftsByAccountIndex(accountIndex, offset, limit int64) (*types.Nfts, error)
 }
 
+// ZkBNBIterators returns iterators over the list apis which page transparently
+type ZkBNBIterators interface {
+	// IterateTxs iterates over all the txs
+	IterateTxs(options ...IteratorOptionFunc) *TxIterator
+
+	// IterateTxsByAccountIndex iterates over the txs of the account
+	IterateTxsByAccountIndex(accountIndex int64, options ...IteratorOptionFunc) *TxIterator
+
+	// IteratePendingTxs iterates over the pending txs
+	IteratePendingTxs(options ...IteratorOptionFunc) *TxIterator
+
+	// IterateExecutedTxs iterates over the executed txs
+	IterateExecutedTxs(options ...IteratorOptionFunc) *TxIterator
+
+	// IterateBlocks iterates over all the blocks
+	IterateBlocks(options ...IteratorOptionFunc) *BlockIterator
+
+	// IterateAccounts iterates over all the accounts
+	IterateAccounts(options ...IteratorOptionFunc) *AccountIterator
+
+	// IterateAssets iterates over all the assets
+	IterateAssets(options ...IteratorOptionFunc) *AssetIterator
+
+	// IterateNftsByAccountIndex iterates over the nfts owned by the account
+	IterateNftsByAccountIndex(accountIndex int64, options ...IteratorOptionFunc) *NftIterator
+}
+
+// ZkBNBWatcher follows the changes of ZkBNB by polling it
+type ZkBNBWatcher interface {
+	// WatchBlocks streams the blocks from fromHeight with their txs as they are produced, then their commit and
+	// verification, until ctx is canceled. The blocks before fromHeight are assumed to be verified.
+	WatchBlocks(ctx context.Context, fromHeight int64, options ...WatchOptionFunc) *BlockSubscription
+
+	// WatchBlocksFromCheckpoint resumes watching blocks from the checkpoint of the last handled event
+	WatchBlocksFromCheckpoint(ctx context.Context, checkpoint BlockCheckpoint, options ...WatchOptionFunc) *BlockSubscription
+
+	// WaitForTx polls the tx until it reaches targetStatus, a failed or expired tx is returned with
+	// a *TxFailedError or *TxExpiredError
+	WaitForTx(ctx context.Context, hash string, targetStatus types.TxStatus, options ...WaitOptionFunc) (*types.EnrichedTx, error)
+
+	// WaitForTxs waits for the txs concurrently, the results are in the order of hashes
+	WaitForTxs(ctx context.Context, hashes []string, targetStatus types.TxStatus, options ...WaitOptionFunc) []*WaitResult
+}
+
+// ZkBNBVerifier checks the signatures of the txs returned by ZkBNB against the keys of the accounts
+type ZkBNBVerifier interface {
+	// VerifyTxByHash fetches the tx and verifies it like VerifyTx
+	VerifyTxByHash(ctx context.Context, hash string) (*TxVerification, error)
+
+	// VerifyTx verifies the signature of the tx against the key of its signer fetched by GetAccountByIndex,
+	// the signatures of the offers of an atomic match tx are verified against the keys of their owners
+	VerifyTx(ctx context.Context, tx *types.Tx) (*TxVerification, error)
+
+	// AuditAccountTxs verifies all the txs of the account
+	AuditAccountTxs(ctx context.Context, accountIndex int64, options ...IteratorOptionFunc) (*AccountAudit, error)
+}
+
+type ZkBNBQuerierWithContext interface {
+	// GetCurrentHeightWithContext is like GetCurrentHeight but with a context
+	GetCurrentHeightWithContext(ctx context.Context) (int64, error)
+
+	// GetBlocksWithContext is like GetBlocks but with a context
+	GetBlocksWithContext(ctx context.Context, offset, limit int64) (uint32, []*types.Block, error)
+
+	// GetBlockByHeightWithContext is like GetBlockByHeight but with a context
+	GetBlockByHeightWithContext(ctx context.Context, blockHeight int64) (*types.Block, error)
+
+	// GetBlockByCommitmentWithContext is like GetBlockByCommitment but with a context
+	GetBlockByCommitmentWithContext(ctx context.Context, blockCommitment string) (*types.Block, error)
+
+	// GetTxWithContext is like GetTx but with a context
+	GetTxWithContext(ctx context.Context, hash string) (*types.EnrichedTx, error)
+
+	// GetTxsByAccountPkWithContext is like GetTxsByAccountPk but with a context
+	GetTxsByAccountPkWithContext(ctx context.Context, accountPk string, offset, limit uint32, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error)
+
+	// GetTxsByAccountNameWithContext is like GetTxsByAccountName but with a context
+	GetTxsByAccountNameWithContext(ctx context.Context, accountName string, offset, limit uint32, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error)
+
+	// GetTxsWithContext is like GetTxs but with a context
+	GetTxsWithContext(ctx context.Context, offset, limit uint32) (total uint32, txs []*types.Tx, err error)
+
+	// GetTxsByAccountIndexWithContext is like GetTxsByAccountIndex but with a context
+	GetTxsByAccountIndexWithContext(ctx context.Context, accountIndex int64, offset, limit uint32, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error)
+
+	// GetTxsByBlockHeightWithContext is like GetTxsByBlockHeight but with a context
+	GetTxsByBlockHeightWithContext(ctx context.Context, blockHeight uint32) ([]*types.Tx, error)
+
+	// GetPendingTxsWithContext is like GetPendingTxs but with a context
+	GetPendingTxsWithContext(ctx context.Context, offset, limit uint32) (total uint32, txs []*types.Tx, err error)
+
+	// GetPendingTxsByAccountNameWithContext is like GetPendingTxsByAccountName but with a context
+	GetPendingTxsByAccountNameWithContext(ctx context.Context, accountName string, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error)
+
+	// GetExecutedTxsWithContext is like GetExecutedTxs but with a context
+	GetExecutedTxsWithContext(ctx context.Context, offset, limit uint32, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error)
+
+	// GetAccountByNameWithContext is like GetAccountByName but with a context
+	GetAccountByNameWithContext(ctx context.Context, accountName string) (*types.Account, error)
+
+	// GetAccountsWithContext is like GetAccounts but with a context
+	GetAccountsWithContext(ctx context.Context, offset, limit uint32) (*types.Accounts, error)
+
+	// GetAccountByPkWithContext is like GetAccountByPk but with a context
+	GetAccountByPkWithContext(ctx context.Context, accountPk string) (*types.Account, error)
+
+	// GetAccountByIndexWithContext is like GetAccountByIndex but with a context
+	GetAccountByIndexWithContext(ctx context.Context, accountIndex int64) (*types.Account, error)
+
+	// GetNextNonceWithContext is like GetNextNonce but with a context
+	GetNextNonceWithContext(ctx context.Context, accountIndex int64) (int64, error)
+
+	// GetMaxOfferIdWithContext is like GetMaxOfferId but with a context
+	GetMaxOfferIdWithContext(ctx context.Context, accountIndex int64) (uint64, error)
+
+	// GetAssetByIdWithContext is like GetAssetById but with a context
+	GetAssetByIdWithContext(ctx context.Context, id uint32) (*types.Asset, error)
+
+	// GetAssetBySymbolWithContext is like GetAssetBySymbol but with a context
+	GetAssetBySymbolWithContext(ctx context.Context, symbol string) (*types.Asset, error)
+
+	// GetAssetsWithContext is like GetAssets but with a context
+	GetAssetsWithContext(ctx context.Context, offset, limit uint32) (*types.Assets, error)
+
+	// GetGasFeeAssetsWithContext is like GetGasFeeAssets but with a context
+	GetGasFeeAssetsWithContext(ctx context.Context) (*types.GasFeeAssets, error)
+
+	// GetGasFeeWithContext is like GetGasFee but with a context
+	GetGasFeeWithContext(ctx context.Context, assetId int64, txType int) (*big.Int, error)
+
+	// SearchWithContext is like Search but with a context
+	SearchWithContext(ctx context.Context, keyword string) (*types.Search, error)
+
+	// GetLayer2BasicInfoWithContext is like GetLayer2BasicInfo but with a context
+	GetLayer2BasicInfoWithContext(ctx context.Context) (*types.Layer2BasicInfo, error)
+
+	// GetGasAccountWithContext is like GetGasAccount but with a context
+	GetGasAccountWithContext(ctx context.Context) (*types.GasAccount, error)
+
+	// GetNftsByAccountIndexWithContext is like GetNftsByAccountIndex but with a context
+	GetNftsByAccountIndexWithContext(ctx context.Context, accountIndex, offset, limit int64) (*types.Nfts, error)
+}
+
 type ZkBNBTxSender interface {
 	// SetKeyManager sets the key manager for signing txs.
 	SetKeyManager(keyManager accounts.KeyManager)
@@ -129,6 +288,9 @@ type ZkBNBTxSender interface {
 	// KeyManager returns the key manager for signing txs.
 	KeyManager() accounts.KeyManager
 
+	// NonceManager returns the nonce manager of the client, nil unless the client is created WithNonceManager
+	NonceManager() *NonceManager
+
 	// SendRawTx sends signed raw transaction and returns tx hash
 	SendRawTx(txType uint32, txInfo string) (string, error)
 
@@ -157,6 +319,113 @@ type ZkBNBTxSender interface {
 
 	// Withdraw will sign tx with key manager and send signed transaction
 	Withdraw(tx *types.WithdrawReq, ops *types.TransactOpts) (string, error)
+
+	// BuildMintNft is like MintNft but returns the signed tx without sending it
+	BuildMintNft(tx *types.MintNftTxReq, ops *types.TransactOpts) (*types.SignedTx, error)
+
+	// BuildCreateCollection is like CreateCollection but returns the signed tx without sending it
+	BuildCreateCollection(tx *types.CreateCollectionReq, ops *types.TransactOpts) (*types.SignedTx, error)
+
+	// BuildCancelOffer is like CancelOffer but returns the signed tx without sending it
+	BuildCancelOffer(tx *types.CancelOfferReq, ops *types.TransactOpts) (*types.SignedTx, error)
+
+	// BuildAtomicMatch is like AtomicMatch but returns the signed tx without sending it
+	BuildAtomicMatch(tx *types.AtomicMatchTxReq, ops *types.TransactOpts) (*types.SignedTx, error)
+
+	// BuildWithdrawNft is like WithdrawNft but returns the signed tx without sending it
+	BuildWithdrawNft(tx *types.WithdrawNftTxReq, ops *types.TransactOpts) (*types.SignedTx, error)
+
+	// BuildTransferNft is like TransferNft but returns the signed tx without sending it
+	BuildTransferNft(tx *types.TransferNftTxReq, ops *types.TransactOpts) (*types.SignedTx, error)
+
+	// BuildTransfer is like Transfer but returns the signed tx without sending it
+	BuildTransfer(tx *types.TransferTxReq, ops *types.TransactOpts) (*types.SignedTx, error)
+
+	// BuildWithdraw is like Withdraw but returns the signed tx without sending it
+	BuildWithdraw(tx *types.WithdrawReq, ops *types.TransactOpts) (*types.SignedTx, error)
+
+	// PrepareTx fills the ops of the tx request like the senders do and returns the tx unsigned, it can be
+	// signed with txutils.SignTx on a machine holding the key and sent later with SendRawTx.
+	// Without key manager, the from account index of the ops must be set.
+	PrepareTx(req interface{}, ops *types.TransactOpts) (*types.UnsignedTx, error)
+
+	// SendTxs signs the txs with consecutive nonces and sends them, the results are in the order of txs.
+	// The gas account, the gas fees and the first nonce are fetched once for the whole batch, the txs are
+	// sent concurrently one by one, or with the sendTxs api with WithBatchEndpoint. The txs rejected for their
+	// nonce are sent again in nonce order.
+	SendTxs(txs []*BatchTx, options ...BatchOptionFunc) []*BatchResult
+
+	// SignOffer signs the offer with key manager, the signed offer can be shared and used in AtomicMatch
+	SignOffer(offer *types.OfferTxInfo) (*types.SignedOffer, error)
+
+	// CreateOffer fills the account index, a new offer id, the listed and expire time and the treasury rate
+	// of the offer if they are not set, then signs it with key manager. The treasury rate defaults to the rate
+	// set with WithOfferTreasuryRate.
+	CreateOffer(tx *types.OfferReq) (*types.SignedOffer, error)
+}
+
+type ZkBNBTxSenderWithContext interface {
+	// SendRawTxWithContext is like SendRawTx but with a context
+	SendRawTxWithContext(ctx context.Context, txType uint32, txInfo string) (string, error)
+
+	// NOTE: You need to call SetKeyManager first before using following functions
+
+	// MintNftWithContext is like MintNft but with a context
+	MintNftWithContext(ctx context.Context, tx *types.MintNftTxReq, ops *types.TransactOpts) (string, error)
+
+	// CreateCollectionWithContext is like CreateCollection but with a context
+	CreateCollectionWithContext(ctx context.Context, tx *types.CreateCollectionReq, ops *types.TransactOpts) (string, error)
+
+	// CancelOfferWithContext is like CancelOffer but with a context
+	CancelOfferWithContext(ctx context.Context, tx *types.CancelOfferReq, ops *types.TransactOpts) (string, error)
+
+	// AtomicMatchWithContext is like AtomicMatch but with a context
+	AtomicMatchWithContext(ctx context.Context, tx *types.AtomicMatchTxReq, ops *types.TransactOpts) (string, error)
+
+	// WithdrawNftWithContext is like WithdrawNft but with a context
+	WithdrawNftWithContext(ctx context.Context, tx *types.WithdrawNftTxReq, ops *types.TransactOpts) (string, error)
+
+	// TransferNftWithContext is like TransferNft but with a context
+	TransferNftWithContext(ctx context.Context, tx *types.TransferNftTxReq, ops *types.TransactOpts) (string, error)
+
+	// TransferWithContext is like Transfer but with a context
+	TransferWithContext(ctx context.Context, tx *types.TransferTxReq, ops *types.TransactOpts) (string, error)
+
+	// WithdrawWithContext is like Withdraw but with a context
+	WithdrawWithContext(ctx context.Context, tx *types.WithdrawReq, ops *types.TransactOpts) (string, error)
+
+	// BuildMintNftWithContext is like BuildMintNft but with a context
+	BuildMintNftWithContext(ctx context.Context, tx *types.MintNftTxReq, ops *types.TransactOpts) (*types.SignedTx, error)
+
+	// BuildCreateCollectionWithContext is like BuildCreateCollection but with a context
+	BuildCreateCollectionWithContext(ctx context.Context, tx *types.CreateCollectionReq, ops *types.TransactOpts) (*types.SignedTx, error)
+
+	// BuildCancelOfferWithContext is like BuildCancelOffer but with a context
+	BuildCancelOfferWithContext(ctx context.Context, tx *types.CancelOfferReq, ops *types.TransactOpts) (*types.SignedTx, error)
+
+	// BuildAtomicMatchWithContext is like BuildAtomicMatch but with a context
+	BuildAtomicMatchWithContext(ctx context.Context, tx *types.AtomicMatchTxReq, ops *types.TransactOpts) (*types.SignedTx, error)
+
+	// BuildWithdrawNftWithContext is like BuildWithdrawNft but with a context
+	BuildWithdrawNftWithContext(ctx context.Context, tx *types.WithdrawNftTxReq, ops *types.TransactOpts) (*types.SignedTx, error)
+
+	// BuildTransferNftWithContext is like BuildTransferNft but with a context
+	BuildTransferNftWithContext(ctx context.Context, tx *types.TransferNftTxReq, ops *types.TransactOpts) (*types.SignedTx, error)
+
+	// BuildTransferWithContext is like BuildTransfer but with a context
+	BuildTransferWithContext(ctx context.Context, tx *types.TransferTxReq, ops *types.TransactOpts) (*types.SignedTx, error)
+
+	// BuildWithdrawWithContext is like BuildWithdraw but with a context
+	BuildWithdrawWithContext(ctx context.Context, tx *types.WithdrawReq, ops *types.TransactOpts) (*types.SignedTx, error)
+
+	// PrepareTxWithContext is like PrepareTx but with a context
+	PrepareTxWithContext(ctx context.Context, req interface{}, ops *types.TransactOpts) (*types.UnsignedTx, error)
+
+	// SendTxsWithContext is like SendTxs but with a context
+	SendTxsWithContext(ctx context.Context, txs []*BatchTx, options ...BatchOptionFunc) []*BatchResult
+
+	// CreateOfferWithContext is like CreateOffer but with a context
+	CreateOfferWithContext(ctx context.Context, tx *types.OfferReq) (*types.SignedOffer, error)
 }
 
 type ZkBNBL1Client interface {
@@ -172,7 +441,7 @@ type ZkBNBL1Client interface {
 	// DepositNft will deposit specific nft to l2
 	DepositNft(nftL1Address common.Address, accountName string, nftL1TokenId *big.Int) (common.Hash, error)
 
-	// RegisterZNS will register account in l2
+	// RegisterZNS will register account in l2, the name is normalized with zns.Label, e.g. "Sher.legend" registers "sher"
 	RegisterZNS(name string, owner common.Address, value *big.Int, pubKeyX [32]byte, pubKeyY [32]byte) (common.Hash, error)
 
 	// RequestFullExit will request full exit from l2
@@ -182,13 +451,72 @@ type ZkBNBL1Client interface {
 	RequestFullExitNft(accountName string, nftIndex uint32) (common.Hash, error)
 }
 
-func NewZkBNBClient(url string) ZkBNBClient {
-	return &l2Client{
-		endpoint: url,
+// NewZkBNBClient returns a client of the ZkBNB api at url. The metrics are disabled when they can not be
+// registered, use NewZkBNBClientWithOptions to get the error.
+func NewZkBNBClient(url string, options ...ClientOptionFunc) ZkBNBClient {
+	c, _ := newL2Client(url, options)
+	return c
+}
+
+// NewZkBNBClientWithOptions is like NewZkBNBClient but fails when the options can not be applied, e.g. the
+// metrics of WithMetricsRegisterer can not be registered.
+func NewZkBNBClientWithOptions(url string, options ...ClientOptionFunc) (ZkBNBClient, error) {
+	c, err := newL2Client(url, options)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// newL2Client returns the client and the error of the options, the client is usable without the features
+// whose options failed.
+func newL2Client(url string, options []ClientOptionFunc) (*l2Client, error) {
+	opt := &clientOption{}
+	for _, f := range options {
+		f(opt)
+	}
+
+	c := &l2Client{
+		endpoint:     url,
+		httpClient:   opt.buildHttpClient(),
+		headers:      opt.headers,
+		retryPolicy:  opt.retryPolicy,
+		interceptors: opt.interceptors,
+
+		roundUpGasFee: opt.roundUpGasFee,
+		batchEndpoint: opt.batchEndpoint,
+		treasuryRate:  defaultTreasuryRate,
+	}
+	if opt.treasuryRate != nil {
+		c.treasuryRate = *opt.treasuryRate
+	}
+	if len(opt.endpoints) > 0 {
+		c.endpoints = newEndpointPool(append([]string{url}, opt.endpoints...), opt.healthCheckInterval, opt.maxHeightLag)
+		c.endpoints.heightFunc = c.getCurrentHeightAt
+	}
+	if opt.nonceManager {
+		c.nonces = newNonceManager(c.remoteNonce)
+	}
+	if opt.tracerProvider != nil {
+		c.tracer = opt.tracerProvider.Tracer(instrumentationName)
+	}
+	var err error
+	if opt.registerer != nil {
+		// the metrics stay disabled if they can not be registered
+		c.metrics, err = newMetrics(opt.registerer)
 	}
+	if c.tracer != nil || c.metrics != nil {
+		c.interceptors = append([]Interceptor{instrumentInterceptor(c.tracer, c.metrics)}, c.interceptors...)
+	}
+	return c, err
 }
 
-func NewZkBNBL1Client(provider, zkbnbContract string) (ZkBNBL1Client, error) {
+func NewZkBNBL1Client(provider, zkbnbContract string, options ...L1ClientOptionFunc) (ZkBNBL1Client, error) {
+	opt := &l1ClientOption{}
+	for _, f := range options {
+		f(opt)
+	}
+
 	bscClient, err := ethclient.Dial(provider)
 	if err != nil {
 		return nil, err
@@ -199,8 +527,18 @@ func NewZkBNBL1Client(provider, zkbnbContract string) (ZkBNBL1Client, error) {
 		panic("new proxy contract error")
 	}
 
-	return &l1Client{
+	c := &l1Client{
 		bscClient:             bscClient,
 		zkbnbContractInstance: zkbnbContractInstance,
-	}, nil
+	}
+	if opt.tracerProvider != nil {
+		c.tracer = opt.tracerProvider.Tracer(instrumentationName)
+	}
+	if opt.registerer != nil {
+		c.metrics, err = newL1Metrics(opt.registerer)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
 }
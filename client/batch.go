@@ -0,0 +1,439 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/txutils"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// BatchTx is one of the txs of SendTxs. Req is a *types.TransferTxReq, *types.WithdrawReq, *types.MintNftTxReq,
+// *types.TransferNftTxReq, *types.WithdrawNftTxReq, *types.CreateCollectionReq, *types.CancelOfferReq or
+// *types.AtomicMatchTxReq, Ops is optional.
+type BatchTx struct {
+	Req interface{}
+	Ops *types.TransactOpts
+}
+
+// BatchResult is the outcome of one of the txs of SendTxs, Hash is set when the tx is accepted.
+type BatchResult struct {
+	TxType uint32
+	Nonce  int64
+	Hash   string
+	Err    error
+}
+
+type batchOption struct {
+	concurrency int
+	size        int
+}
+
+type BatchOptionFunc func(*batchOption)
+
+// BatchWithConcurrency sets the max number of txs SendTxs signs or sends at the same time, 8 by default
+func BatchWithConcurrency(concurrency int) BatchOptionFunc {
+	return func(o *batchOption) {
+		o.concurrency = concurrency
+	}
+}
+
+// BatchWithSize sets the max number of txs posted at once to the sendTxs api with WithBatchEndpoint, 100 by default
+func BatchWithSize(size int) BatchOptionFunc {
+	return func(o *batchOption) {
+		o.size = size
+	}
+}
+
+func newBatchOption(options []BatchOptionFunc) *batchOption {
+	opt := &batchOption{
+		concurrency: 8,
+		size:        100,
+	}
+	for _, f := range options {
+		f(opt)
+	}
+	if opt.concurrency <= 0 {
+		opt.concurrency = 1
+	}
+	if opt.size <= 0 {
+		opt.size = 1
+	}
+	return opt
+}
+
+// WithBatchEndpoint makes SendTxs send the signed txs with the sendTxs api of the server, in batches of the size set
+// with BatchWithSize. ZkBNB has no such api, only enable it for the servers or gateways which have it.
+func WithBatchEndpoint() ClientOptionFunc {
+	return func(o *clientOption) {
+		o.batchEndpoint = true
+	}
+}
+
+type batchItem struct {
+	*BatchResult
+	to   string
+	sign func(ops *types.TransactOpts) (string, error)
+	ops  *types.TransactOpts
+	// autoNonce is true when the nonce is not set by the caller
+	autoNonce bool
+	// managed is true when the nonce is handed out by the nonce manager and not answered yet
+	managed bool
+	txInfo  string
+}
+
+func (c *l2Client) SendTxs(txs []*BatchTx, options ...BatchOptionFunc) []*BatchResult {
+	return c.SendTxsWithContext(context.Background(), txs, options...)
+}
+
+func (c *l2Client) SendTxsWithContext(ctx context.Context, txs []*BatchTx, options ...BatchOptionFunc) []*BatchResult {
+	ctx, end := startSpan(ctx, c.tracer, "zkbnb.SendTxs")
+	defer end(nil)
+
+	opt := newBatchOption(options)
+	items := make([]*batchItem, len(txs))
+	results := make([]*BatchResult, len(txs))
+	for i, tx := range txs {
+		items[i] = c.newBatchItem(tx)
+		results[i] = items[i].BatchResult
+	}
+	if c.keyManager == nil {
+		failBatch(items, fmt.Errorf("key manager is nil"))
+		return results
+	}
+
+	c.fillBatch(ctx, items)
+	forEachBatchItem(okBatchItems(items), opt.concurrency, func(item *batchItem) {
+		item.txInfo, item.Err = item.sign(item.ops)
+		if item.Err != nil && item.managed {
+			item.managed = false
+			c.nonces.done(item.ops.FromAccountIndex, item.Nonce, errNotSent)
+		}
+	})
+	c.sendBatch(ctx, okBatchItems(items), opt)
+
+	var rejected []*batchItem
+	for _, item := range items {
+		if errors.Is(item.Err, ErrInvalidNonce) {
+			rejected = append(rejected, item)
+		}
+	}
+	c.resendBatch(ctx, rejected)
+	return results
+}
+
+func (c *l2Client) newBatchItem(tx *BatchTx) *batchItem {
+	item := &batchItem{BatchResult: &BatchResult{}, ops: new(types.TransactOpts)}
+	if tx.Ops != nil {
+		*item.ops = *tx.Ops
+	}
+	txType, err := types.ReqTxType(tx.Req)
+	if err != nil {
+		item.Err = err
+		return item
+	}
+	item.TxType, item.to = txType, txReceiver(tx.Req)
+	req, err := resolveTxReq(tx.Req)
+	if err != nil {
+		item.Err = err
+		return item
+	}
+	item.sign = func(ops *types.TransactOpts) (string, error) {
+		return txutils.ConstructTx(c.keyManager, req, ops)
+	}
+	item.ops.TxType = int(item.TxType)
+	item.autoNonce = item.ops.Nonce == 0
+	return item
+}
+
+// lookup caches the result of a lookup of a batch, including a failure
+type lookup[T any] struct {
+	done  bool
+	value T
+	err   error
+}
+
+func (l *lookup[T]) get(fetch func() (T, error)) (T, error) {
+	if !l.done {
+		l.value, l.err = fetch()
+		l.done = true
+	}
+	return l.value, l.err
+}
+
+func lookupOf[K comparable, T any](lookups map[K]*lookup[T], key K) *lookup[T] {
+	l, ok := lookups[key]
+	if !ok {
+		l = &lookup[T]{}
+		lookups[key] = l
+	}
+	return l
+}
+
+// fillBatch fills the ops of the txs like fullFillDefaultOps does, the gas account, the account of the key
+// manager, the gas fees and the receivers are fetched once for the whole batch. The nonces are consecutive
+// from the next nonce of the account, or handed out by the nonce manager.
+func (c *l2Client) fillBatch(ctx context.Context, items []*batchItem) {
+	gasAccount := &lookup[int64]{}
+	account := &lookup[int64]{}
+	gasFees := make(map[[2]int64]*lookup[*big.Int])
+	receivers := make(map[string]*lookup[*types.TransactOpts])
+	nonces := make(map[int64]*lookup[int64])
+
+	fill := func(item *batchItem) error {
+		ops := item.ops
+		if item.to != "" {
+			to, err := lookupOf(receivers, item.to).get(func() (*types.TransactOpts, error) {
+				return c.fullFillToAddrOps(ctx, new(types.TransactOpts), item.to)
+			})
+			if err != nil {
+				return err
+			}
+			ops.ToAccountIndex = to.ToAccountIndex
+			ops.ToAccountNameHash = to.ToAccountNameHash
+		}
+		if ops.GasAccountIndex == 0 {
+			index, err := gasAccount.get(func() (int64, error) {
+				gasAccount, err := c.GetGasAccountWithContext(ctx)
+				if err != nil {
+					return 0, err
+				}
+				if gasAccount.Index == 0 {
+					return 0, fmt.Errorf("get gas account error, gas account index is %d", gasAccount.Index)
+				}
+				return gasAccount.Index, nil
+			})
+			if err != nil {
+				return err
+			}
+			ops.GasAccountIndex = index
+		}
+		if ops.ExpiredAt == 0 {
+			ops.ExpiredAt = time.Now().Add(defaultExpireTime).UnixMilli()
+		}
+		if ops.FromAccountIndex == 0 {
+			index, err := account.get(func() (int64, error) {
+				l2Account, err := c.GetAccountByPkWithContext(ctx, hex.EncodeToString(c.keyManager.PubKey().Bytes()))
+				if err != nil {
+					return 0, err
+				}
+				return l2Account.Index, nil
+			})
+			if err != nil {
+				return err
+			}
+			ops.FromAccountIndex = index
+		}
+		if len(ops.CallDataHash) == 0 {
+			hFunc := mimc.NewMiMC()
+			ops.CallDataHash = hFunc.Sum([]byte(ops.CallData))
+		}
+		if ops.GasFee == nil && ops.GasFeeAssetAmount == nil {
+			fee, err := lookupOf(gasFees, [2]int64{ops.GasFeeAssetId, int64(ops.TxType)}).get(func() (*big.Int, error) {
+				return c.GetGasFeeWithContext(ctx, ops.GasFeeAssetId, ops.TxType)
+			})
+			if err != nil {
+				return err
+			}
+			ops.GasFeeAssetAmount = fee
+		}
+		if err := c.checkGasFee(ops); err != nil {
+			return err
+		}
+		if !item.autoNonce {
+			return nil
+		}
+		if c.nonces != nil {
+			nonce, err := c.nonces.Next(ctx, ops.FromAccountIndex)
+			if err != nil {
+				return err
+			}
+			ops.Nonce = nonce
+			item.managed = true
+			return nil
+		}
+		next := lookupOf(nonces, ops.FromAccountIndex)
+		nonce, err := next.get(func() (int64, error) {
+			return c.remoteNonce(ctx, ops.FromAccountIndex)
+		})
+		if err != nil {
+			return err
+		}
+		ops.Nonce = nonce
+		next.value++
+		return nil
+	}
+
+	for _, item := range okBatchItems(items) {
+		item.Err = fill(item)
+		item.Nonce = item.ops.Nonce
+	}
+}
+
+// sendBatch sends the signed txs with the sendTxs api if the client is created with WithBatchEndpoint, or one by
+// one concurrently
+func (c *l2Client) sendBatch(ctx context.Context, items []*batchItem, opt *batchOption) {
+	if c.batchEndpoint {
+		for len(items) > 0 {
+			n := opt.size
+			if n > len(items) {
+				n = len(items)
+			}
+			c.postTxs(ctx, items[:n])
+			items = items[n:]
+		}
+		return
+	}
+
+	forEachBatchItem(items, opt.concurrency, func(item *batchItem) {
+		item.Hash, item.Err = c.sendSigned(ctx, item.TxType, item.ops, item.txInfo, item.managed)
+		item.managed = false
+	})
+}
+
+// postTxs posts the txs to the sendTxs api
+func (c *l2Client) postTxs(ctx context.Context, items []*batchItem) {
+	rawTxs := make([]*types.RawTx, len(items))
+	for i, item := range items {
+		rawTxs[i] = &types.RawTx{TxType: item.TxType, TxInfo: item.txInfo}
+	}
+	data, err := json.Marshal(rawTxs)
+	if err != nil {
+		failBatch(items, err)
+		c.doneBatch(items)
+		return
+	}
+
+	// the txs of the account sent by other senders with a lower nonce go first
+	for _, item := range items {
+		if item.managed {
+			if err := c.nonces.waitTurn(ctx, item.ops.FromAccountIndex, item.Nonce); err != nil {
+				failBatch(items, err)
+				c.doneBatch(items)
+				return
+			}
+			break
+		}
+	}
+
+	res := &types.SendTxResults{}
+	err = c.postForm(ctx, "SendTxs", "/api/v1/sendTxs", url.Values{"txs": {string(data)}}, res)
+	if err == nil && len(res.Results) != len(items) {
+		err = fmt.Errorf("sendTxs returned %d results for %d txs", len(res.Results), len(items))
+	}
+	if err != nil {
+		c.findSentBatch(ctx, items, err)
+	} else {
+		for i, item := range items {
+			result := res.Results[i]
+			if result.Code == 0 {
+				item.Hash = result.TxHash
+				continue
+			}
+			txErr := &APIError{StatusCode: http.StatusBadRequest, Code: result.Code, Message: result.Message, Path: "/api/v1/sendTxs"}
+			txErr.matchKind()
+			item.Err = txErr
+		}
+	}
+	for _, item := range items {
+		c.metrics.observeTxSent(int(item.TxType), item.Err)
+	}
+	c.doneBatch(items)
+}
+
+// findSentBatch looks up the txs of a batch whose sending failed, the server may have accepted some of them before
+// failing. The txs known by ZkBNB are sent, the others fail with err.
+func (c *l2Client) findSentBatch(ctx context.Context, items []*batchItem, err error) {
+	for _, item := range items {
+		hash, findErr := c.findSentTx(ctx, item.TxType, item.txInfo)
+		if findErr != nil || hash == "" {
+			item.Err = err
+			continue
+		}
+		item.Hash = hash
+	}
+}
+
+// doneBatch records the answers of the txs whose nonce was handed out by the nonce manager
+func (c *l2Client) doneBatch(items []*batchItem) {
+	for _, item := range items {
+		if item.managed {
+			item.managed = false
+			c.nonces.done(item.ops.FromAccountIndex, item.Nonce, item.Err)
+		}
+	}
+}
+
+// resendBatch sends the txs rejected for their nonce again one by one in nonce order, since a tx whose nonce
+// arrived before the lower ones is rejected. A tx rejected again is signed with a new nonce if the caller
+// did not set it.
+func (c *l2Client) resendBatch(ctx context.Context, items []*batchItem) {
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Nonce < items[j].Nonce })
+	for _, item := range items {
+		item.Hash, item.Err = c.SendRawTxWithContext(ctx, item.TxType, item.txInfo)
+		if !item.autoNonce || !errors.Is(item.Err, ErrInvalidNonce) {
+			continue
+		}
+
+		if c.nonces != nil {
+			item.ops.Nonce, item.Err = c.nonces.Next(ctx, item.ops.FromAccountIndex)
+			item.managed = item.Err == nil
+		} else {
+			item.ops.Nonce, item.Err = c.remoteNonce(ctx, item.ops.FromAccountIndex)
+		}
+		if item.Err != nil {
+			continue
+		}
+		item.Nonce = item.ops.Nonce
+		item.Hash, item.Err = c.signAndSendOnce(ctx, item.TxType, item.ops, item.managed, item.sign)
+		item.managed = false
+	}
+}
+
+func okBatchItems(items []*batchItem) []*batchItem {
+	var ok []*batchItem
+	for _, item := range items {
+		if item.Err == nil {
+			ok = append(ok, item)
+		}
+	}
+	return ok
+}
+
+func failBatch(items []*batchItem, err error) {
+	for _, item := range items {
+		if item.Err == nil {
+			item.Err = err
+		}
+	}
+}
+
+// forEachBatchItem runs f on the items with at most concurrency items at the same time, the items are started
+// in order
+func forEachBatchItem(items []*batchItem, concurrency int, f func(item *batchItem)) {
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, item := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(item *batchItem) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			f(item)
+		}(item)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,216 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/txutils"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+	"github.com/bnb-chain/zkbnb-go-sdk/zkbnbtest"
+)
+
+// operationCounter counts the api calls of a client by operation
+type operationCounter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (o *operationCounter) intercept(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+	o.mu.Lock()
+	o.counts[req.Operation]++
+	o.mu.Unlock()
+	return next(ctx, req)
+}
+
+func (o *operationCounter) count(operation string) int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.counts[operation]
+}
+
+// getBatchClient returns a client of the server of getSdkClient which counts its api calls
+func getBatchClient(t *testing.T, options ...ClientOptionFunc) (*l2Client, *zkbnbtest.Server, *operationCounter) {
+	sdkClient, server := getSdkClient(t)
+	counter := &operationCounter{counts: make(map[string]int)}
+	c := NewZkBNBClient(server.URL, append(options, WithInterceptors(counter.intercept))...).(*l2Client)
+	c.SetKeyManager(sdkClient.KeyManager())
+	return c, server, counter
+}
+
+func batchTxs() []*BatchTx {
+	return []*BatchTx{
+		{Req: &types.TransferTxReq{ToAccountName: "gavin.legend", AssetAmount: big.NewInt(100)}},
+		{Req: &types.MintNftTxReq{To: "gavin.legend", NftContentHash: txutils.NftContentHash("batch")}},
+		{Req: &types.TransferNftTxReq{To: "gavin.legend", NftIndex: 3}},
+		{Req: &types.TransferTxReq{ToAccountName: "gavin.legend", AssetId: 1, AssetAmount: big.NewInt(100)}},
+		{Req: &types.WithdrawReq{AssetAmount: big.NewInt(100), ToAddress: "0x8b2C5A5744F42AA9269BaabDd05933a96D8EF911"}},
+		{Req: &types.WithdrawNftTxReq{AccountIndex: 2, NftIndex: 17, ToAddress: "0x8b2C5A5744F42AA9269BaabDd05933a96D8EF911"}},
+		{Req: &types.TransferTxReq{ToAccountName: "gavin.legend", AssetAmount: big.NewInt(100)}},
+	}
+}
+
+func assertBatchSent(t *testing.T, server *zkbnbtest.Server, results []*BatchResult, nonces []int64) {
+	accepted := server.AcceptedTxs()
+	require.Len(t, results, len(nonces))
+	for i, result := range results {
+		if assert.NoError(t, result.Err) {
+			assert.NotEmpty(t, result.Hash)
+			assert.Equal(t, nonces[i], result.Nonce)
+		}
+	}
+	assert.ElementsMatch(t, nonces, sentNonces(t, server))
+	assert.Len(t, accepted, len(nonces))
+}
+
+func TestSendTxs(t *testing.T) {
+	sdkClient, server, counter := getBatchClient(t)
+
+	results := sdkClient.SendTxs(batchTxs(), BatchWithConcurrency(4))
+	assertBatchSent(t, server, results, []int64{0, 1, 2, 3, 4, 5, 6})
+	assert.Equal(t, types.TxTypeMintNft, int(results[1].TxType))
+	for _, result := range results {
+		hash, err := txutils.TxHash(result.TxType, findSentTxInfo(t, server, result.Hash))
+		require.NoError(t, err)
+		assert.Equal(t, hash, result.Hash)
+	}
+
+	assert.Equal(t, 1, counter.count("GetGasAccount"))
+	assert.Equal(t, 1, counter.count("GetAccountByName"))
+	assert.Equal(t, 1, counter.count("GetNextNonce"))
+	// the fees of transfer, mint nft, transfer nft, withdraw and withdraw nft
+	assert.Equal(t, 5, counter.count("GetGasFee"))
+	assert.Zero(t, counter.count("SendTxs"), "the sendTxs api is only used with WithBatchEndpoint")
+}
+
+// findSentTxInfo returns the tx info of a tx accepted by the server
+func findSentTxInfo(t *testing.T, server *zkbnbtest.Server, hash string) string {
+	for _, sent := range server.AcceptedTxs() {
+		if sent.Hash == hash {
+			return sent.TxInfo
+		}
+	}
+	require.Fail(t, "tx not sent", hash)
+	return ""
+}
+
+func TestSendTxsBatchEndpoint(t *testing.T) {
+	sdkClient, server, counter := getBatchClient(t, WithBatchEndpoint())
+	server.SetBatchSend(true)
+
+	results := sdkClient.SendTxs(batchTxs(), BatchWithSize(3))
+	assertBatchSent(t, server, results, []int64{0, 1, 2, 3, 4, 5, 6})
+	assert.Equal(t, 3, counter.count("SendTxs"))
+	assert.Zero(t, counter.count("SendRawTx"))
+}
+
+func TestSendTxsItemErrors(t *testing.T) {
+	sdkClient, server, _ := getBatchClient(t, WithBatchEndpoint())
+	server.SetBatchSend(true)
+
+	results := sdkClient.SendTxs([]*BatchTx{
+		{Req: &types.TransferTxReq{ToAccountName: "gavin.legend", AssetAmount: big.NewInt(100)}},
+		{Req: &types.TransferTxReq{ToAccountName: "nobody.legend", AssetAmount: big.NewInt(100)}},
+		{Req: &types.OfferReq{}},
+		{Req: &types.TransferTxReq{ToAccountName: "gavin.legend", AssetAmount: big.NewInt(100)}},
+		{Req: &types.TransferNftTxReq{To: "gavin.legend", NftIndex: 100}},
+	})
+
+	assert.NoError(t, results[0].Err)
+	assert.ErrorIs(t, results[1].Err, ErrAccountNotFound)
+	assert.EqualError(t, results[2].Err, "unsupported tx request *types.OfferReq")
+	assert.NoError(t, results[3].Err)
+	assert.ErrorIs(t, results[4].Err, ErrNftNotFound)
+	assert.Equal(t, []int64{0, 1}, sentNonces(t, server), "the txs failing before signing take no nonce")
+	assert.Equal(t, int64(2), results[4].Nonce)
+}
+
+// failSendTxs makes the sendTxs calls fail like a gateway error, after the server handled them if accepted is set
+func failSendTxs(accepted bool) Interceptor {
+	return func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+		if req.Operation != "SendTxs" {
+			return next(ctx, req)
+		}
+		if accepted {
+			if _, err := next(ctx, req); err != nil {
+				return nil, err
+			}
+		}
+		return nil, &APIError{StatusCode: http.StatusBadGateway, Path: req.Path}
+	}
+}
+
+func TestSendTxsBatchEndpointFailure(t *testing.T) {
+	// the server accepts the txs but the response is lost
+	sdkClient, server, counter := getBatchClient(t, WithBatchEndpoint(), WithInterceptors(failSendTxs(true)))
+	server.SetBatchSend(true)
+
+	results := sdkClient.SendTxs(batchTxs()[:3])
+	assertBatchSent(t, server, results, []int64{0, 1, 2})
+	assert.Equal(t, 1, counter.count("SendTxs"))
+	assert.Equal(t, 3, counter.count("GetTx"), "the txs are looked up")
+	assert.Zero(t, counter.count("SendRawTx"))
+
+	// the server fails before accepting the txs
+	sdkClient, server, _ = getBatchClient(t, WithBatchEndpoint(), WithInterceptors(failSendTxs(false)))
+	server.SetBatchSend(true)
+
+	results = sdkClient.SendTxs(batchTxs()[:3])
+	for _, result := range results {
+		var apiErr *APIError
+		if assert.ErrorAs(t, result.Err, &apiErr) {
+			assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+		}
+		assert.Empty(t, result.Hash)
+	}
+	assert.Empty(t, server.SentTxs())
+}
+
+func TestSendTxsResendsOnNonceError(t *testing.T) {
+	sdkClient, server, _ := getBatchClient(t)
+
+	// the account sent 3 txs the next nonce api does not know of yet
+	server.SetNonce(2, 3)
+	calls := 0
+	server.Handle("/api/v1/nextNonce", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			_, _ = w.Write([]byte(`{"nonce":0}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"nonce":3}`))
+	})
+
+	results := sdkClient.SendTxs(batchTxs()[:3])
+	assertBatchSent(t, server, results, []int64{3, 4, 5})
+}
+
+func TestSendTxsWithNonceManager(t *testing.T) {
+	sdkClient, server, _ := getBatchClient(t, WithNonceManager())
+
+	_, err := sdkClient.Transfer(testTransfer(), nil)
+	require.NoError(t, err)
+	results := sdkClient.SendTxs(batchTxs())
+	for _, result := range results {
+		assert.NoError(t, result.Err)
+	}
+	assert.ElementsMatch(t, []int64{0, 1, 2, 3, 4, 5, 6, 7}, sentNonces(t, server))
+
+	state := sdkClient.NonceManager().State(2)
+	assert.Equal(t, int64(8), state.Local)
+	assert.Equal(t, int64(8), state.Remote)
+	assert.Zero(t, state.InFlight)
+}
+
+func TestSendTxsWithoutKeyManager(t *testing.T) {
+	sdkClient := NewZkBNBClient("http://127.0.0.1:1")
+	results := sdkClient.SendTxs(batchTxs()[:2])
+	for _, result := range results {
+		assert.EqualError(t, result.Err, "key manager is nil")
+	}
+}
@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/txutils"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// errSentLater marks a handed out nonce whose tx is signed to be sent later
+var errSentLater = errors.New("tx sent later")
+
+// txReceiver returns the account name of the receiver of the tx request, empty if the tx has none
+func txReceiver(req interface{}) string {
+	switch req := req.(type) {
+	case *types.TransferTxReq:
+		return req.ToAccountName
+	case *types.MintNftTxReq:
+		return req.To
+	case *types.TransferNftTxReq:
+		return req.To
+	}
+	return ""
+}
+
+// resolveTxReq checks the amounts of the tx request and derives the content hash of a mint nft request, so that the
+// request fails before any lookup and is self contained
+func resolveTxReq(req interface{}) (interface{}, error) {
+	if err := checkTxAmount(req); err != nil {
+		return nil, err
+	}
+	if mint, ok := req.(*types.MintNftTxReq); ok {
+		return txutils.ResolveNftContent(mint)
+	}
+	return req, nil
+}
+
+func (c *l2Client) PrepareTx(req interface{}, ops *types.TransactOpts) (*types.UnsignedTx, error) {
+	return c.PrepareTxWithContext(context.Background(), req, ops)
+}
+
+func (c *l2Client) PrepareTxWithContext(ctx context.Context, req interface{}, ops *types.TransactOpts) (tx *types.UnsignedTx, err error) {
+	ctx, end := startSpan(ctx, c.tracer, "zkbnb.PrepareTx")
+	defer func() { end(err) }()
+
+	tx, err = c.prepareTx(ctx, req, ops)
+	if err != nil {
+		return nil, err
+	}
+	c.takeNonce(tx.Ops, errSentLater)
+	return tx, nil
+}
+
+// prepareTx fills the ops of the tx request like the senders do, a nonce handed out by the nonce manager is
+// still in flight when it returns
+func (c *l2Client) prepareTx(ctx context.Context, req interface{}, ops *types.TransactOpts) (*types.UnsignedTx, error) {
+	txType, err := types.ReqTxType(req)
+	if err != nil {
+		return nil, err
+	}
+	if ops == nil {
+		ops = new(types.TransactOpts)
+	}
+	if c.keyManager == nil && ops.FromAccountIndex == 0 {
+		return nil, fmt.Errorf("key manager is nil, the from account index of the ops must be set")
+	}
+	req, err = resolveTxReq(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ops.TxType = int(txType)
+	if to := txReceiver(req); to != "" {
+		ops, err = c.fullFillToAddrOps(ctx, ops, to)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ops, err = c.fullFillDefaultOps(ctx, ops)
+	if err != nil {
+		return nil, err
+	}
+	return &types.UnsignedTx{Version: types.UnsignedTxVersion, TxType: txType, Req: req, Ops: ops}, nil
+}
+
+// takeNonce records the outcome of a prepared tx whose nonce was handed out by the nonce manager
+func (c *l2Client) takeNonce(ops *types.TransactOpts, err error) {
+	if c.nonces != nil {
+		c.nonces.done(ops.FromAccountIndex, ops.Nonce, err)
+	}
+}
+
+// buildTx fills the ops of the tx request, then signs it without sending it
+func (c *l2Client) buildTx(ctx context.Context, operation string, req interface{}, ops *types.TransactOpts) (tx *types.SignedTx, err error) {
+	ctx, end := startSpan(ctx, c.tracer, "zkbnb."+operation)
+	defer func() { end(err) }()
+
+	if c.keyManager == nil {
+		return nil, fmt.Errorf("key manager is nil")
+	}
+	unsigned, err := c.prepareTx(ctx, req, ops)
+	if err != nil {
+		return nil, err
+	}
+	tx, err = txutils.SignTx(c.keyManager, unsigned)
+	if err != nil {
+		c.takeNonce(unsigned.Ops, errNotSent)
+		return nil, err
+	}
+	c.takeNonce(unsigned.Ops, errSentLater)
+	return tx, nil
+}
+
+func (c *l2Client) BuildMintNft(tx *types.MintNftTxReq, ops *types.TransactOpts) (*types.SignedTx, error) {
+	return c.BuildMintNftWithContext(context.Background(), tx, ops)
+}
+
+func (c *l2Client) BuildMintNftWithContext(ctx context.Context, tx *types.MintNftTxReq, ops *types.TransactOpts) (*types.SignedTx, error) {
+	return c.buildTx(ctx, "BuildMintNft", tx, ops)
+}
+
+func (c *l2Client) BuildCreateCollection(tx *types.CreateCollectionReq, ops *types.TransactOpts) (*types.SignedTx, error) {
+	return c.BuildCreateCollectionWithContext(context.Background(), tx, ops)
+}
+
+func (c *l2Client) BuildCreateCollectionWithContext(ctx context.Context, tx *types.CreateCollectionReq, ops *types.TransactOpts) (*types.SignedTx, error) {
+	return c.buildTx(ctx, "BuildCreateCollection", tx, ops)
+}
+
+func (c *l2Client) BuildCancelOffer(tx *types.CancelOfferReq, ops *types.TransactOpts) (*types.SignedTx, error) {
+	return c.BuildCancelOfferWithContext(context.Background(), tx, ops)
+}
+
+func (c *l2Client) BuildCancelOfferWithContext(ctx context.Context, tx *types.CancelOfferReq, ops *types.TransactOpts) (*types.SignedTx, error) {
+	return c.buildTx(ctx, "BuildCancelOffer", tx, ops)
+}
+
+func (c *l2Client) BuildAtomicMatch(tx *types.AtomicMatchTxReq, ops *types.TransactOpts) (*types.SignedTx, error) {
+	return c.BuildAtomicMatchWithContext(context.Background(), tx, ops)
+}
+
+func (c *l2Client) BuildAtomicMatchWithContext(ctx context.Context, tx *types.AtomicMatchTxReq, ops *types.TransactOpts) (*types.SignedTx, error) {
+	return c.buildTx(ctx, "BuildAtomicMatch", tx, ops)
+}
+
+func (c *l2Client) BuildWithdrawNft(tx *types.WithdrawNftTxReq, ops *types.TransactOpts) (*types.SignedTx, error) {
+	return c.BuildWithdrawNftWithContext(context.Background(), tx, ops)
+}
+
+func (c *l2Client) BuildWithdrawNftWithContext(ctx context.Context, tx *types.WithdrawNftTxReq, ops *types.TransactOpts) (*types.SignedTx, error) {
+	return c.buildTx(ctx, "BuildWithdrawNft", tx, ops)
+}
+
+func (c *l2Client) BuildTransferNft(tx *types.TransferNftTxReq, ops *types.TransactOpts) (*types.SignedTx, error) {
+	return c.BuildTransferNftWithContext(context.Background(), tx, ops)
+}
+
+func (c *l2Client) BuildTransferNftWithContext(ctx context.Context, tx *types.TransferNftTxReq, ops *types.TransactOpts) (*types.SignedTx, error) {
+	return c.buildTx(ctx, "BuildTransferNft", tx, ops)
+}
+
+func (c *l2Client) BuildWithdraw(tx *types.WithdrawReq, ops *types.TransactOpts) (*types.SignedTx, error) {
+	return c.BuildWithdrawWithContext(context.Background(), tx, ops)
+}
+
+func (c *l2Client) BuildWithdrawWithContext(ctx context.Context, tx *types.WithdrawReq, ops *types.TransactOpts) (*types.SignedTx, error) {
+	return c.buildTx(ctx, "BuildWithdraw", tx, ops)
+}
+
+func (c *l2Client) BuildTransfer(tx *types.TransferTxReq, ops *types.TransactOpts) (*types.SignedTx, error) {
+	return c.BuildTransferWithContext(context.Background(), tx, ops)
+}
+
+func (c *l2Client) BuildTransferWithContext(ctx context.Context, tx *types.TransferTxReq, ops *types.TransactOpts) (*types.SignedTx, error) {
+	return c.buildTx(ctx, "BuildTransfer", tx, ops)
+}
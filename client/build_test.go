@@ -0,0 +1,113 @@
+package client
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/txutils"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+func TestBuildTransfer(t *testing.T) {
+	sdkClient, server := getSdkClient(t)
+
+	signed, err := sdkClient.BuildTransfer(&types.TransferTxReq{ToAccountName: "gavin.legend", AssetAmount: big.NewInt(100)}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, server.SentTxs(), "the tx is not sent")
+
+	assert.Equal(t, uint32(types.TxTypeTransfer), signed.TxType)
+	assert.Equal(t, types.TxTypeTransfer, signed.Ops.TxType)
+	assert.Equal(t, int64(2), signed.Ops.FromAccountIndex)
+	assert.Equal(t, int64(3), signed.Ops.ToAccountIndex)
+	assert.Equal(t, int64(1), signed.Ops.GasAccountIndex)
+	assert.Equal(t, int64(0), signed.Ops.Nonce)
+	hash, err := txutils.TxHash(signed.TxType, signed.TxInfo)
+	require.NoError(t, err)
+	assert.Equal(t, hash, signed.Hash)
+
+	txHash, err := sdkClient.SendRawTx(signed.TxType, signed.TxInfo)
+	require.NoError(t, err)
+	assert.Equal(t, signed.Hash, txHash)
+}
+
+func TestBuildTxs(t *testing.T) {
+	sdkClient, _ := getSdkClient(t)
+	pubKey := hex.EncodeToString(sdkClient.KeyManager().PubKey().Bytes())
+	address := "0x8b2C5A5744F42AA9269BaabDd05933a96D8EF911"
+
+	build := []func() (*types.SignedTx, error){
+		func() (*types.SignedTx, error) {
+			return sdkClient.BuildWithdraw(&types.WithdrawReq{AssetAmount: big.NewInt(100), ToAddress: address}, nil)
+		},
+		func() (*types.SignedTx, error) {
+			return sdkClient.BuildCreateCollection(&types.CreateCollectionReq{Name: "collection"}, nil)
+		},
+		func() (*types.SignedTx, error) {
+			return sdkClient.BuildMintNft(&types.MintNftTxReq{To: "gavin.legend", NftContentHash: txutils.NftContentHash("build")}, nil)
+		},
+		func() (*types.SignedTx, error) {
+			return sdkClient.BuildTransferNft(&types.TransferNftTxReq{To: "gavin.legend", NftIndex: 3}, nil)
+		},
+		func() (*types.SignedTx, error) {
+			return sdkClient.BuildWithdrawNft(&types.WithdrawNftTxReq{AccountIndex: 2, NftIndex: 17, ToAddress: address}, nil)
+		},
+		func() (*types.SignedTx, error) {
+			return sdkClient.BuildCancelOffer(&types.CancelOfferReq{OfferId: 1}, nil)
+		},
+	}
+	for _, f := range build {
+		signed, err := f()
+		require.NoError(t, err)
+		assert.NoError(t, txutils.VerifyTxSignature(signed.TxType, signed.TxInfo, pubKey))
+		assert.Equal(t, int(signed.TxType), signed.Ops.TxType)
+	}
+}
+
+func TestOfflineSigning(t *testing.T) {
+	sdkClient, server := getSdkClient(t)
+
+	// the online machine has no key
+	online := NewZkBNBClient(server.URL)
+	_, err := online.PrepareTx(&types.TransferTxReq{ToAccountName: "gavin.legend", AssetAmount: big.NewInt(100)}, nil)
+	assert.EqualError(t, err, "key manager is nil, the from account index of the ops must be set")
+
+	unsigned, err := online.PrepareTx(
+		&types.TransferTxReq{ToAccountName: "gavin.legend", AssetAmount: big.NewInt(100)},
+		&types.TransactOpts{FromAccountIndex: 2},
+	)
+	require.NoError(t, err)
+	data, err := types.MarshalUnsignedTx(unsigned)
+	require.NoError(t, err)
+
+	// the offline machine holds the key
+	parsed, err := types.ParseUnsignedTx(data)
+	require.NoError(t, err)
+	signed, err := txutils.SignTx(sdkClient.KeyManager(), parsed)
+	require.NoError(t, err)
+
+	txHash, err := online.SendRawTx(signed.TxType, signed.TxInfo)
+	require.NoError(t, err)
+	assert.Equal(t, signed.Hash, txHash)
+	assert.Equal(t, txHash, lastAcceptedTx(t, server).Hash)
+}
+
+func TestBuildWithNonceManager(t *testing.T) {
+	c, server := newNonceClient(t)
+
+	signed, err := c.BuildTransfer(testTransfer(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), signed.Ops.Nonce)
+	state := c.NonceManager().State(2)
+	assert.Equal(t, int64(1), state.Local, "the nonce of the built tx is taken")
+	assert.Zero(t, state.InFlight)
+
+	_, err = c.SendRawTx(signed.TxType, signed.TxInfo)
+	require.NoError(t, err)
+	_, err = c.Transfer(testTransfer(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{0, 1}, sentNonces(t, server))
+}
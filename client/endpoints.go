@@ -0,0 +1,249 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultHealthCheckInterval = 30 * time.Second
+	defaultMaxHeightLag        = 5
+	// stickyIntervals is how many health check intervals an unused sticky endpoint is kept
+	stickyIntervals = 4
+)
+
+// WithEndpoints adds replicas of the ZkBNB api, the requests are balanced over all the healthy endpoints
+// and the GET requests fail over to the next endpoint on transient errors.
+func WithEndpoints(endpoints ...string) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.endpoints = append(o.endpoints, endpoints...)
+	}
+}
+
+// WithHealthCheck sets how often the endpoints are health checked, and how many blocks an endpoint
+// may lag behind the highest one before it is skipped
+func WithHealthCheck(interval time.Duration, maxHeightLag int64) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.healthCheckInterval = interval
+		o.maxHeightLag = maxHeightLag
+	}
+}
+
+type stickyAccountKey struct{}
+
+type stickyTxKey struct{}
+
+type acceptedEndpointKey struct{}
+
+// withStickyAccount marks the requests made with ctx as sent on behalf of the account, these requests
+// stick to the same endpoint so that the nonce read from an endpoint matches the txs sent to it.
+func withStickyAccount(ctx context.Context, accountIndex int64) context.Context {
+	return context.WithValue(ctx, stickyAccountKey{}, accountIndex)
+}
+
+// withStickyTx marks the requests made with ctx as querying the tx, they go to the endpoint which accepted
+// the tx as the other endpoints may not know it yet.
+func withStickyTx(ctx context.Context, txHash string) context.Context {
+	return context.WithValue(ctx, stickyTxKey{}, txHash)
+}
+
+// withAcceptedEndpoint records the endpoint answering the requests made with ctx into endpoint.
+func withAcceptedEndpoint(ctx context.Context, endpoint **endpointState) context.Context {
+	return context.WithValue(ctx, acceptedEndpointKey{}, endpoint)
+}
+
+type endpointState struct {
+	url     string
+	healthy bool
+	height  int64
+}
+
+// stickyEntry is the endpoint of an account or a tx, it expires when it is not used for a while.
+type stickyEntry struct {
+	endpoint *endpointState
+	expires  time.Time
+}
+
+// stickyAccount and stickyTx are the keys of the sticky endpoints
+type (
+	stickyAccount int64
+	stickyTx      string
+)
+
+type endpointPool struct {
+	mu        sync.Mutex
+	endpoints []*endpointState
+	sticky    map[interface{}]stickyEntry
+	lastPrune time.Time
+	next      uint32
+
+	interval     time.Duration
+	maxHeightLag int64
+	lastCheck    time.Time
+	checking     int32
+	heightFunc   func(ctx context.Context, endpoint string) (int64, error)
+}
+
+func newEndpointPool(urls []string, interval time.Duration, maxHeightLag int64) *endpointPool {
+	if interval == 0 {
+		interval = defaultHealthCheckInterval
+	}
+	if maxHeightLag == 0 {
+		maxHeightLag = defaultMaxHeightLag
+	}
+	pool := &endpointPool{
+		sticky:       make(map[interface{}]stickyEntry),
+		interval:     interval,
+		maxHeightLag: maxHeightLag,
+		// all the endpoints are assumed to be healthy until the first check
+		lastCheck: time.Now(),
+	}
+	for _, url := range urls {
+		pool.endpoints = append(pool.endpoints, &endpointState{url: url, healthy: true})
+	}
+	return pool
+}
+
+// candidates returns the endpoints in the order they should be tried: the sticky endpoint of the tx or else
+// of the account, then the healthy endpoints in round-robin order, then the unhealthy ones as a last resort.
+func (p *endpointPool) candidates(ctx context.Context) []*endpointState {
+	p.maybeCheckHealth()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	start := int(atomic.AddUint32(&p.next, 1)) % len(p.endpoints)
+	result := make([]*endpointState, 0, len(p.endpoints))
+	var sticky *endpointState
+	for _, key := range stickyKeys(ctx) {
+		if entry, ok := p.sticky[key]; ok && entry.endpoint.healthy && time.Now().Before(entry.expires) {
+			sticky = entry.endpoint
+			result = append(result, sticky)
+			break
+		}
+	}
+	for _, healthy := range []bool{true, false} {
+		for i := range p.endpoints {
+			endpoint := p.endpoints[(start+i)%len(p.endpoints)]
+			if endpoint.healthy == healthy && endpoint != sticky {
+				result = append(result, endpoint)
+			}
+		}
+	}
+	return result
+}
+
+// do calls the endpoints one by one until a call succeeds or fails with a non transient error. Without failover
+// only the first endpoint is called, a request which may have taken effect, like a POST, must not be replayed on
+// another endpoint.
+func (p *endpointPool) do(ctx context.Context, failover bool, call func(endpoint string) error) error {
+	var err error
+	for _, endpoint := range p.candidates(ctx) {
+		err = call(endpoint.url)
+		if err != nil && isTransientError(err, defaultRetryStatusCodes) {
+			p.markUnhealthy(endpoint)
+			if !failover {
+				return err
+			}
+			continue
+		}
+		if err == nil {
+			if accepted, ok := ctx.Value(acceptedEndpointKey{}).(**endpointState); ok {
+				*accepted = endpoint
+			}
+		}
+		if accountIndex, ok := ctx.Value(stickyAccountKey{}).(int64); ok {
+			p.stick(stickyAccount(accountIndex), endpoint)
+		}
+		return err
+	}
+	return err
+}
+
+// stickyKeys returns the sticky keys of the requests made with ctx, the most specific first.
+func stickyKeys(ctx context.Context) []interface{} {
+	var keys []interface{}
+	if txHash, ok := ctx.Value(stickyTxKey{}).(string); ok {
+		keys = append(keys, stickyTx(txHash))
+	}
+	if accountIndex, ok := ctx.Value(stickyAccountKey{}).(int64); ok {
+		keys = append(keys, stickyAccount(accountIndex))
+	}
+	return keys
+}
+
+// stick sends the next requests of key to the endpoint, the expired entries are pruned every few
+// health check intervals so that the entries of the accounts and txs no longer used are dropped.
+func (p *endpointPool) stick(key interface{}, endpoint *endpointState) {
+	ttl := stickyIntervals * p.interval
+	now := time.Now()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if now.Sub(p.lastPrune) >= ttl {
+		for key, entry := range p.sticky {
+			if now.After(entry.expires) {
+				delete(p.sticky, key)
+			}
+		}
+		p.lastPrune = now
+	}
+	p.sticky[key] = stickyEntry{endpoint: endpoint, expires: now.Add(ttl)}
+}
+
+func (p *endpointPool) markUnhealthy(endpoint *endpointState) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	endpoint.healthy = false
+}
+
+func (p *endpointPool) maybeCheckHealth() {
+	p.mu.Lock()
+	due := time.Since(p.lastCheck) >= p.interval
+	p.mu.Unlock()
+	if !due || p.heightFunc == nil || !atomic.CompareAndSwapInt32(&p.checking, 0, 1) {
+		return
+	}
+	go func() {
+		defer atomic.StoreInt32(&p.checking, 0)
+		ctx, cancel := context.WithTimeout(context.Background(), p.interval)
+		defer cancel()
+		p.checkHealth(ctx)
+	}()
+}
+
+// checkHealth queries the height of every endpoint, the endpoints that fail or lag behind are marked unhealthy.
+func (p *endpointPool) checkHealth(ctx context.Context) {
+	heights := make([]int64, len(p.endpoints))
+	errs := make([]error, len(p.endpoints))
+	var wg sync.WaitGroup
+	for i, endpoint := range p.endpoints {
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+			heights[i], errs[i] = p.heightFunc(ctx, url)
+		}(i, endpoint.url)
+	}
+	wg.Wait()
+
+	maxHeight := int64(0)
+	for i := range p.endpoints {
+		if errs[i] == nil && heights[i] > maxHeight {
+			maxHeight = heights[i]
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, endpoint := range p.endpoints {
+		if errs[i] != nil {
+			endpoint.healthy = false
+			continue
+		}
+		endpoint.height = heights[i]
+		endpoint.healthy = maxHeight-heights[i] <= p.maxHeightLag
+	}
+	p.lastCheck = time.Now()
+}
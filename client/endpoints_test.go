@@ -0,0 +1,185 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/txutils"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+type replica struct {
+	*httptest.Server
+	height int64
+	down   int32
+	calls  int32
+}
+
+func newReplica(height int64) *replica {
+	r := &replica{height: height}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&r.calls, 1)
+		if atomic.LoadInt32(&r.down) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"height":%d,"nonce":%d}`, r.height, r.height)))
+	}))
+	return r
+}
+
+func TestEndpointFailover(t *testing.T) {
+	first, second := newReplica(10), newReplica(10)
+	defer first.Close()
+	defer second.Close()
+	atomic.StoreInt32(&first.down, 1)
+
+	c := NewZkBNBClient(first.URL, WithEndpoints(second.URL)).(*l2Client)
+	for i := 0; i < 4; i++ {
+		height, err := c.GetCurrentHeight()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(10), height)
+	}
+	// the failed endpoint is skipped until it is health checked again
+	assert.Equal(t, int32(1), atomic.LoadInt32(&first.calls))
+}
+
+func TestEndpointRoundRobin(t *testing.T) {
+	first, second := newReplica(10), newReplica(10)
+	defer first.Close()
+	defer second.Close()
+
+	c := NewZkBNBClient(first.URL, WithEndpoints(second.URL))
+	for i := 0; i < 4; i++ {
+		_, err := c.GetCurrentHeight()
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&first.calls))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&second.calls))
+}
+
+func TestEndpointHealthCheckSkipsLaggingEndpoint(t *testing.T) {
+	first, second := newReplica(100), newReplica(10)
+	defer first.Close()
+	defer second.Close()
+
+	c := NewZkBNBClient(first.URL, WithEndpoints(second.URL)).(*l2Client)
+	c.endpoints.checkHealth(context.Background())
+	assert.True(t, c.endpoints.endpoints[0].healthy)
+	assert.False(t, c.endpoints.endpoints[1].healthy)
+
+	for i := 0; i < 4; i++ {
+		height, err := c.GetCurrentHeight()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(100), height)
+	}
+}
+
+func TestEndpointStickyAccount(t *testing.T) {
+	first, second := newReplica(10), newReplica(11)
+	defer first.Close()
+	defer second.Close()
+
+	c := NewZkBNBClient(first.URL, WithEndpoints(second.URL)).(*l2Client)
+	ctx := withStickyAccount(context.Background(), 3)
+	nonce, err := c.GetNextNonceWithContext(ctx, 3)
+	assert.NoError(t, err)
+	for i := 0; i < 4; i++ {
+		next, err := c.GetNextNonceWithContext(ctx, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, nonce, next)
+	}
+}
+
+func TestEndpointNoFailoverForPost(t *testing.T) {
+	txInfo := signedTestTransfer(t)
+	txHash, err := txutils.TxHash(types.TxTypeTransfer, txInfo)
+	assert.NoError(t, err)
+
+	// the replicas share the same backend, which accepts the tx before the gateway fails
+	var sends, accepted int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/sendTx":
+			atomic.AddInt32(&sends, 1)
+			atomic.StoreInt32(&accepted, 1)
+			w.WriteHeader(http.StatusBadGateway)
+		case "/api/v1/tx":
+			if atomic.LoadInt32(&accepted) == 0 {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"code":29404,"message":"tx not found"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"hash":"` + txHash + `"}`))
+		}
+	})
+	first, second := httptest.NewServer(handler), httptest.NewServer(handler)
+	defer first.Close()
+	defer second.Close()
+
+	c := NewZkBNBClient(first.URL, WithEndpoints(second.URL), WithRetryPolicy(testRetryPolicy()))
+	hash, err := c.SendRawTx(types.TxTypeTransfer, txInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, txHash, hash)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&sends), "the tx is sent once")
+}
+
+func TestEndpointStickyTx(t *testing.T) {
+	txInfo := signedTestTransfer(t)
+	txHash, err := txutils.TxHash(types.TxTypeTransfer, txInfo)
+	assert.NoError(t, err)
+
+	// each replica only knows the txs sent to it until they are synced
+	newServer := func() *httptest.Server {
+		var accepted int32
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/api/v1/sendTx":
+				atomic.StoreInt32(&accepted, 1)
+				_, _ = w.Write([]byte(`{"tx_hash":"` + txHash + `"}`))
+			case "/api/v1/tx":
+				if atomic.LoadInt32(&accepted) == 0 {
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"code":29404,"message":"tx not found"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"hash":"` + txHash + `","status":1}`))
+			}
+		}))
+	}
+	first, second := newServer(), newServer()
+	defer first.Close()
+	defer second.Close()
+
+	c := NewZkBNBClient(first.URL, WithEndpoints(second.URL))
+	hash, err := c.SendRawTx(types.TxTypeTransfer, txInfo)
+	assert.NoError(t, err)
+	for i := 0; i < 4; i++ {
+		tx, err := c.GetTx(hash)
+		assert.NoError(t, err)
+		assert.Equal(t, txHash, tx.Hash)
+	}
+	_, err = c.WaitForTx(context.Background(), hash, types.TxStatusPending, fastPolls)
+	assert.NoError(t, err)
+}
+
+func TestEndpointStickyExpiry(t *testing.T) {
+	pool := newEndpointPool([]string{"http://first", "http://second"}, time.Millisecond, 0)
+	ctx := withStickyAccount(context.Background(), 1)
+	pool.stick(stickyAccount(1), pool.endpoints[1])
+	pool.stick(stickyTx("hash"), pool.endpoints[1])
+	assert.Equal(t, pool.endpoints[1], pool.candidates(ctx)[0])
+
+	time.Sleep(stickyIntervals * time.Millisecond * 2)
+	// the expired entries are neither used nor kept
+	assert.Equal(t, pool.endpoints[0], pool.candidates(ctx)[0])
+	pool.stick(stickyAccount(2), pool.endpoints[0])
+	assert.Len(t, pool.sticky, 1)
+}
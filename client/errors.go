@@ -0,0 +1,160 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// Sentinel errors returned (wrapped in an *APIError) by the l2 client. Use errors.Is to check for them.
+var (
+	ErrNotFound            = errors.New("not found")
+	ErrAccountNotFound     = errors.New("account not found")
+	ErrTxNotFound          = errors.New("tx not found")
+	ErrBlockNotFound       = errors.New("block not found")
+	ErrAssetNotFound       = errors.New("asset not found")
+	ErrNftNotFound         = errors.New("nft not found")
+	ErrInvalidParam        = errors.New("invalid param")
+	ErrInvalidNonce        = errors.New("invalid nonce")
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrInvalidGasFee       = errors.New("invalid gas fee")
+	ErrInvalidExpireTime   = errors.New("invalid expire time")
+	ErrInvalidSignature    = errors.New("invalid signature")
+	ErrNftNotOwned         = errors.New("nft not owned")
+	ErrOfferUsed           = errors.New("offer already used")
+	ErrInternal            = errors.New("internal server error")
+)
+
+// apiErrorCodes maps the error codes of the ZkBNB api server to sentinel errors, the errors
+// without a code of their own are matched by message.
+var apiErrorCodes = map[int]error{
+	types.CodeInvalidExpireTime: ErrInvalidExpireTime,
+	types.CodeInvalidGasFee:     ErrInvalidGasFee,
+	types.CodeBalanceNotEnough:  ErrInsufficientBalance,
+	types.CodeInvalidNonce:      ErrInvalidNonce,
+}
+
+// apiGenericErrorCodes maps the error codes shared by several errors to sentinel errors, they are used
+// when the message matches no specific error.
+var apiGenericErrorCodes = map[int]error{
+	types.CodeInvalidParam:   ErrInvalidParam,
+	types.CodeInvalidTxField: ErrInvalidParam,
+	types.CodeNotFound:       ErrNotFound,
+	types.CodeInternal:       ErrInternal,
+}
+
+// apiErrorKinds maps the messages returned by the ZkBNB api server to sentinel errors,
+// the first matched entry wins.
+var apiErrorKinds = []struct {
+	message string
+	err     error
+}{
+	{"account not found", ErrAccountNotFound},
+	{"account nonce not found", ErrAccountNotFound},
+	{"tx not found", ErrTxNotFound},
+	{"block not found", ErrBlockNotFound},
+	{"asset not found", ErrAssetNotFound},
+	{"nft not found", ErrNftNotFound},
+	{"invalid nonce", ErrInvalidNonce},
+	{"balance is not enough", ErrInsufficientBalance},
+	{"insufficient balance", ErrInsufficientBalance},
+	{"invalid gas fee", ErrInvalidGasFee},
+	{"gas fee too low", ErrInvalidGasFee},
+	{"invalid expired time", ErrInvalidExpireTime},
+	{"invalid expire time", ErrInvalidExpireTime},
+	{"invalid signature", ErrInvalidSignature},
+	{"not owner of the nft", ErrNftNotOwned},
+	{"offer id is already used", ErrOfferUsed},
+	{"invalid param", ErrInvalidParam},
+	{"invalid tx field", ErrInvalidParam},
+	{"not found", ErrNotFound},
+	{"internal server error", ErrInternal},
+}
+
+// APIError is returned when the ZkBNB api server responds with a non 200 status.
+type APIError struct {
+	// StatusCode is the http status code of the response
+	StatusCode int
+	// Code is the error code returned by the server, 0 if the body carries no code
+	Code int
+	// Message is the error message returned by the server
+	Message string
+	// Path is the requested api path, e.g. /api/v1/account
+	Path string
+
+	kind error
+}
+
+type apiErrorBody struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func newAPIError(statusCode int, path string, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Path:       path,
+	}
+	errBody := &apiErrorBody{}
+	if err := json.Unmarshal(body, errBody); err == nil && (errBody.Code != 0 || errBody.Message != "") {
+		apiErr.Code = errBody.Code
+		apiErr.Message = errBody.Message
+	} else {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+
+	apiErr.matchKind()
+	return apiErr
+}
+
+// matchKind sets the sentinel error of the error from its code, message and status code
+func (e *APIError) matchKind() {
+	if kind, ok := apiErrorCodes[e.Code]; ok {
+		e.kind = kind
+		return
+	}
+	message := strings.ToLower(e.Message)
+	for _, kind := range apiErrorKinds {
+		if strings.Contains(message, kind.message) {
+			e.kind = kind.err
+			return
+		}
+	}
+	if kind, ok := apiGenericErrorCodes[e.Code]; ok {
+		e.kind = kind
+		return
+	}
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		e.kind = ErrNotFound
+	case e.StatusCode >= http.StatusInternalServerError:
+		e.kind = ErrInternal
+	}
+}
+
+func (e *APIError) Error() string {
+	if e.Code != 0 {
+		return fmt.Sprintf("%s: %s (status %d, code %d)", e.Path, e.Message, e.StatusCode, e.Code)
+	}
+	return fmt.Sprintf("%s: %s (status %d)", e.Path, e.Message, e.StatusCode)
+}
+
+// Unwrap returns the sentinel error matched from the server response, if any.
+func (e *APIError) Unwrap() error {
+	return e.kind
+}
+
+// Is reports whether the error matches target, all the specific not found errors also match ErrNotFound.
+func (e *APIError) Is(target error) bool {
+	if target == ErrNotFound {
+		switch e.kind {
+		case ErrNotFound, ErrAccountNotFound, ErrTxNotFound, ErrBlockNotFound, ErrAssetNotFound, ErrNftNotFound:
+			return true
+		}
+	}
+	return false
+}
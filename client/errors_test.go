@@ -0,0 +1,102 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+func newErrorServer(status int, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+func TestAPIErrorFromJsonBody(t *testing.T) {
+	server := newErrorServer(http.StatusBadRequest, `{"code":29404,"message":"account not found"}`)
+	defer server.Close()
+
+	c := &l2Client{endpoint: server.URL}
+	_, err := c.GetAccountByName("sher.legend")
+	assert.True(t, errors.Is(err, ErrAccountNotFound))
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.False(t, errors.Is(err, ErrTxNotFound))
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, types.CodeNotFound, apiErr.Code)
+	assert.Equal(t, "account not found", apiErr.Message)
+	assert.Equal(t, "/api/v1/account", apiErr.Path)
+}
+
+func TestAPIErrorCodes(t *testing.T) {
+	tests := []struct {
+		code int
+		kind error
+	}{
+		{types.CodeInvalidParam, ErrInvalidParam},
+		{types.CodeInvalidTxField, ErrInvalidParam},
+		{types.CodeInvalidExpireTime, ErrInvalidExpireTime},
+		{types.CodeInvalidGasFee, ErrInvalidGasFee},
+		{types.CodeBalanceNotEnough, ErrInsufficientBalance},
+		{types.CodeInvalidNonce, ErrInvalidNonce},
+		{types.CodeNotFound, ErrNotFound},
+		{types.CodeInternal, ErrInternal},
+	}
+	for _, test := range tests {
+		// the messages match no error, the errors are matched by code
+		server := newErrorServer(http.StatusBadRequest, fmt.Sprintf(`{"code":%d,"message":"unexpected"}`, test.code))
+		c := &l2Client{endpoint: server.URL}
+		_, err := c.SendRawTx(4, "{}")
+		assert.ErrorIs(t, err, test.kind, test.code)
+		server.Close()
+	}
+}
+
+func TestAPIErrorKinds(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		kind   error
+	}{
+		{http.StatusBadRequest, `{"code":21003,"message":"balance is not enough"}`, ErrInsufficientBalance},
+		{http.StatusBadRequest, `{"code":21010,"message":"invalid nonce, actual: 3, expected: 4"}`, ErrInvalidNonce},
+		{http.StatusBadRequest, `{"code":21002,"message":"invalid gas fee amount"}`, ErrInvalidGasFee},
+		{http.StatusBadRequest, `{"code":20001,"message":"invalid param: hash"}`, ErrInvalidParam},
+		{http.StatusBadRequest, `{"code":21010,"message":"nonce too low"}`, ErrInvalidNonce},
+		{http.StatusBadRequest, `{"code":20002,"message":"invalid signature"}`, ErrInvalidSignature},
+		{http.StatusBadRequest, `{"code":20002,"message":"account 2 is not owner of the nft 3"}`, ErrNftNotOwned},
+		{http.StatusBadRequest, `{"code":20002,"message":"offer id is already used"}`, ErrOfferUsed},
+		{http.StatusBadRequest, `{"code":29404,"message":"pool does not exist"}`, ErrNotFound},
+		{http.StatusBadRequest, `{"code":29500,"message":"unexpected error"}`, ErrInternal},
+		{http.StatusNotFound, `page not found`, ErrNotFound},
+		{http.StatusBadGateway, `bad gateway`, ErrInternal},
+	}
+	for _, test := range tests {
+		server := newErrorServer(test.status, test.body)
+		c := &l2Client{endpoint: server.URL}
+		_, err := c.SendRawTx(4, "{}")
+		assert.True(t, errors.Is(err, test.kind), test.body)
+
+		var apiErr *APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, test.status, apiErr.StatusCode)
+		server.Close()
+	}
+
+	// the generic message patterns of other errors do not match the offer error
+	server := newErrorServer(http.StatusBadRequest, `{"code":20001,"message":"invalid param: name is already used"}`)
+	defer server.Close()
+	c := &l2Client{endpoint: server.URL}
+	_, err := c.SendRawTx(4, "{}")
+	assert.ErrorIs(t, err, ErrInvalidParam)
+	assert.NotErrorIs(t, err, ErrOfferUsed)
+}
@@ -0,0 +1,254 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+const instrumentationName = "github.com/bnb-chain/zkbnb-go-sdk/client"
+
+// WithTracerProvider enables tracing, a span is emitted for every api call and every tx sent by the client
+func WithTracerProvider(tracerProvider trace.TracerProvider) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.tracerProvider = tracerProvider
+	}
+}
+
+// WithMetricsRegisterer enables the prometheus metrics of the client and registers them to registerer,
+// NewZkBNBClientWithOptions fails when they can not be registered
+func WithMetricsRegisterer(registerer prometheus.Registerer) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.registerer = registerer
+	}
+}
+
+// WithL1TracerProvider enables tracing, a span is emitted for every contract tx sent by the l1 client
+func WithL1TracerProvider(tracerProvider trace.TracerProvider) L1ClientOptionFunc {
+	return func(o *l1ClientOption) {
+		o.tracerProvider = tracerProvider
+	}
+}
+
+// WithL1MetricsRegisterer enables the prometheus metrics of the l1 client and registers them to registerer,
+// NewZkBNBL1Client fails when they can not be registered
+func WithL1MetricsRegisterer(registerer prometheus.Registerer) L1ClientOptionFunc {
+	return func(o *l1ClientOption) {
+		o.registerer = registerer
+	}
+}
+
+var txTypeNames = map[int]string{
+	types.TxTypeEmpty:            "empty",
+	types.TxTypeRegisterZns:      "register_zns",
+	types.TxTypeDeposit:          "deposit",
+	types.TxTypeDepositNft:       "deposit_nft",
+	types.TxTypeTransfer:         "transfer",
+	types.TxTypeWithdraw:         "withdraw",
+	types.TxTypeCreateCollection: "create_collection",
+	types.TxTypeMintNft:          "mint_nft",
+	types.TxTypeTransferNft:      "transfer_nft",
+	types.TxTypeAtomicMatch:      "atomic_match",
+	types.TxTypeCancelOffer:      "cancel_offer",
+	types.TxTypeWithdrawNft:      "withdraw_nft",
+	types.TxTypeFullExit:         "full_exit",
+	types.TxTypeFullExitNft:      "full_exit_nft",
+	types.TxTypeOffer:            "offer",
+}
+
+func txTypeName(txType int) string {
+	if name, ok := txTypeNames[txType]; ok {
+		return name
+	}
+	return strconv.Itoa(txType)
+}
+
+// metrics are the prometheus metrics of a l2 client
+type metrics struct {
+	apiRequests    *prometheus.CounterVec
+	apiLatency     *prometheus.HistogramVec
+	txsSent        *prometheus.CounterVec
+	txSendFailures *prometheus.CounterVec
+}
+
+func newMetrics(registerer prometheus.Registerer) (*metrics, error) {
+	r := &registration{registerer: registerer}
+	m := &metrics{
+		apiRequests: register(r, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "zkbnb",
+			Name:      "api_requests_total",
+			Help:      "Number of ZkBNB api calls by operation and status.",
+		}, []string{"operation", "status"})),
+		apiLatency: register(r, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "zkbnb",
+			Name:      "api_request_duration_seconds",
+			Help:      "Latency of ZkBNB api calls by operation.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation"})),
+		txsSent: register(r, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "zkbnb",
+			Name:      "txs_sent_total",
+			Help:      "Number of l2 txs sent by tx type.",
+		}, []string{"tx_type"})),
+		txSendFailures: register(r, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "zkbnb",
+			Name:      "tx_send_failures_total",
+			Help:      "Number of l2 txs failed to be sent by tx type.",
+		}, []string{"tx_type"})),
+	}
+	if err := r.done(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// l1Metrics are the prometheus metrics of a l1 client
+type l1Metrics struct {
+	l1Txs       *prometheus.CounterVec
+	l1TxLatency *prometheus.HistogramVec
+}
+
+func newL1Metrics(registerer prometheus.Registerer) (*l1Metrics, error) {
+	r := &registration{registerer: registerer}
+	m := &l1Metrics{
+		l1Txs: register(r, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "zkbnb",
+			Name:      "l1_txs_total",
+			Help:      "Number of l1 contract txs by method and status.",
+		}, []string{"method", "status"})),
+		l1TxLatency: register(r, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "zkbnb",
+			Name:      "l1_tx_duration_seconds",
+			Help:      "Latency of sending l1 contract txs by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"})),
+	}
+	if err := r.done(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// registration registers the collectors of a client and keeps the first error.
+type registration struct {
+	registerer prometheus.Registerer
+	// registered are the collectors registered by this client, not shared with other clients
+	registered []prometheus.Collector
+	err        error
+}
+
+// register registers the collector, or returns the registered one if several clients share the registerer.
+// When the collector can not be registered, e.g. a collector of another kind has its name, the error is kept.
+func register[T prometheus.Collector](r *registration, collector T) T {
+	if err := r.registerer.Register(collector); err != nil {
+		var registeredErr prometheus.AlreadyRegisteredError
+		if errors.As(err, &registeredErr) {
+			if existing, ok := registeredErr.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+		if r.err == nil {
+			r.err = fmt.Errorf("failed to register zkbnb metrics: %w", err)
+		}
+		return collector
+	}
+	r.registered = append(r.registered, collector)
+	return collector
+}
+
+// done returns the registration error, the collectors registered by this client are unregistered on error so that
+// no metric is exported without being recorded.
+func (r *registration) done() error {
+	if r.err != nil {
+		for _, collector := range r.registered {
+			r.registerer.Unregister(collector)
+		}
+	}
+	return r.err
+}
+
+// instrumentInterceptor traces and measures every api call.
+func instrumentInterceptor(tracer trace.Tracer, m *metrics) Interceptor {
+	return func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+		var span trace.Span
+		if tracer != nil {
+			ctx, span = tracer.Start(ctx, "zkbnb."+req.Operation, trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					attribute.String("http.method", req.Method),
+					attribute.String("zkbnb.path", req.Path),
+				))
+			defer span.End()
+		}
+
+		start := time.Now()
+		resp, err := next(ctx, req)
+
+		status := "error"
+		if resp != nil {
+			status = strconv.Itoa(resp.StatusCode)
+		}
+		if span != nil {
+			if resp != nil {
+				span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+			}
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+		}
+		if m != nil {
+			m.apiRequests.WithLabelValues(req.Operation, status).Inc()
+			m.apiLatency.WithLabelValues(req.Operation).Observe(time.Since(start).Seconds())
+		}
+		return resp, err
+	}
+}
+
+// startSpan starts a span around a client method, the returned function ends it with the method error.
+func startSpan(ctx context.Context, tracer trace.Tracer, name string, attrs ...attribute.KeyValue) (context.Context, func(err error)) {
+	if tracer == nil {
+		return ctx, func(error) {}
+	}
+	ctx, span := tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+// observeTxSent records the result of sending a l2 tx
+func (m *metrics) observeTxSent(txType int, err error) {
+	if m == nil {
+		return
+	}
+	if err != nil {
+		m.txSendFailures.WithLabelValues(txTypeName(txType)).Inc()
+		return
+	}
+	m.txsSent.WithLabelValues(txTypeName(txType)).Inc()
+}
+
+// observeL1Tx records the result of sending a l1 contract tx
+func (m *l1Metrics) observeL1Tx(method string, start time.Time, err error) {
+	if m == nil {
+		return
+	}
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+	m.l1Txs.WithLabelValues(method, status).Inc()
+	m.l1TxLatency.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/accounts"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+func newTestTracerProvider() (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)), recorder
+}
+
+func TestInstrumentTransfer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/gasAccount":
+			_, _ = w.Write([]byte(`{"status":1,"index":1,"name":"gas.legend"}`))
+		case "/api/v1/account":
+			_, _ = w.Write([]byte(`{"index":2,"name":"sher.legend"}`))
+		case "/api/v1/nextNonce":
+			_, _ = w.Write([]byte(`{"nonce":5}`))
+		case "/api/v1/gasFee":
+			_, _ = w.Write([]byte(`{"gas_fee":"1000"}`))
+		case "/api/v1/sendTx":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":21010,"message":"invalid nonce"}`))
+		}
+	}))
+	defer server.Close()
+
+	tracerProvider, recorder := newTestTracerProvider()
+	registry := prometheus.NewRegistry()
+	c := NewZkBNBClient(server.URL, WithTracerProvider(tracerProvider), WithMetricsRegisterer(registry))
+	keyManager, err := accounts.NewSeedKeyManager(seed)
+	assert.NoError(t, err)
+	c.SetKeyManager(keyManager)
+
+	_, err = c.Transfer(&types.TransferTxReq{ToAccountName: "gavin.legend", AssetAmount: big.NewInt(1)}, nil)
+	assert.ErrorIs(t, err, ErrInvalidNonce)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	transfer := spans["zkbnb.Transfer"]
+	fullFill := spans["zkbnb.fullFillDefaultOps"]
+	assert.NotNil(t, transfer)
+	assert.NotNil(t, fullFill)
+	assert.Equal(t, codes.Error, transfer.Status().Code)
+	assert.Equal(t, transfer.SpanContext().SpanID(), fullFill.Parent().SpanID())
+	for _, name := range []string{"zkbnb.GetGasAccount", "zkbnb.GetAccountByPk", "zkbnb.GetNextNonce", "zkbnb.GetGasFee"} {
+		assert.Equal(t, fullFill.SpanContext().SpanID(), spans[name].Parent().SpanID(), name)
+	}
+	assert.Equal(t, transfer.SpanContext().SpanID(), spans["zkbnb.GetAccountByName"].Parent().SpanID())
+	assert.Equal(t, transfer.SpanContext().SpanID(), spans["zkbnb.SendRawTx"].Parent().SpanID())
+	assert.Equal(t, codes.Error, spans["zkbnb.SendRawTx"].Status().Code)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(c.(*l2Client).metrics.apiRequests.WithLabelValues("GetNextNonce", "200")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(c.(*l2Client).metrics.apiRequests.WithLabelValues("SendRawTx", "400")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(c.(*l2Client).metrics.txSendFailures.WithLabelValues("transfer")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(c.(*l2Client).metrics.txsSent.WithLabelValues("transfer")))
+	assert.Equal(t, 6, testutil.CollectAndCount(registry, "zkbnb_api_request_duration_seconds"))
+}
+
+func TestInstrumentSendRawTx(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"tx_hash":"hash"}`))
+	}))
+	defer server.Close()
+
+	registry := prometheus.NewRegistry()
+	c := NewZkBNBClient(server.URL, WithMetricsRegisterer(registry))
+	// clients sharing a registerer share the collectors
+	other := NewZkBNBClient(server.URL, WithMetricsRegisterer(registry))
+
+	_, err := c.SendRawTx(types.TxTypeTransfer, signedTestTransfer(t))
+	assert.NoError(t, err)
+	_, err = other.SendRawTx(types.TxTypeTransfer, signedTestTransfer(t))
+	assert.NoError(t, err)
+
+	expected := `
+# HELP zkbnb_txs_sent_total Number of l2 txs sent by tx type.
+# TYPE zkbnb_txs_sent_total counter
+zkbnb_txs_sent_total{tx_type="transfer"} 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "zkbnb_txs_sent_total"))
+}
+
+func TestInstrumentL1Client(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"node is syncing"}}`))
+	}))
+	defer server.Close()
+
+	tracerProvider, recorder := newTestTracerProvider()
+	registry := prometheus.NewRegistry()
+	c, err := NewZkBNBL1Client(server.URL, "0x0000000000000000000000000000000000000001",
+		WithL1TracerProvider(tracerProvider), WithL1MetricsRegisterer(registry))
+	assert.NoError(t, err)
+	assert.NoError(t, c.SetPrivateKey("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"))
+
+	_, err = c.DepositBNB("sher", big.NewInt(1))
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "zkbnb.l1.DepositBNB", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, float64(1), testutil.ToFloat64(c.(*l1Client).metrics.l1Txs.WithLabelValues("DepositBNB", "error")))
+	// the l1 client does not register the l2 metrics
+	l2Metrics, err := newMetrics(prometheus.NewRegistry())
+	assert.NoError(t, err)
+	assert.NoError(t, registry.Register(l2Metrics.apiRequests))
+}
+
+func TestInstrumentRegisterConflict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"tx_hash":"hash"}`))
+	}))
+	defer server.Close()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(prometheus.NewGauge(prometheus.GaugeOpts{Namespace: "zkbnb", Name: "txs_sent_total"}))
+	_, err := NewZkBNBClientWithOptions(server.URL, WithMetricsRegisterer(registry))
+	assert.ErrorContains(t, err, "failed to register zkbnb metrics")
+
+	// the metrics are disabled and none of them is left registered
+	c := NewZkBNBClient(server.URL, WithMetricsRegisterer(registry))
+	assert.Nil(t, c.(*l2Client).metrics)
+	assert.Empty(t, c.(*l2Client).interceptors)
+	_, err = c.SendRawTx(types.TxTypeTransfer, signedTestTransfer(t))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, testutil.CollectAndCount(registry, "zkbnb_txs_sent_total"))
+	l2Metrics, err := newMetrics(prometheus.NewRegistry())
+	assert.NoError(t, err)
+	assert.NoError(t, registry.Register(l2Metrics.apiRequests))
+	// the l2 client does not register the l1 metrics
+	l1Metrics, err := newL1Metrics(prometheus.NewRegistry())
+	assert.NoError(t, err)
+	assert.NoError(t, registry.Register(l1Metrics.l1Txs))
+}
+
+func TestInstrumentL1RegisterConflict(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(prometheus.NewGauge(prometheus.GaugeOpts{Namespace: "zkbnb", Name: "l1_txs_total"}))
+	_, err := NewZkBNBL1Client("http://localhost", "0x0000000000000000000000000000000000000001",
+		WithL1MetricsRegisterer(registry))
+	assert.ErrorContains(t, err, "failed to register zkbnb metrics")
+}
+
+func TestInstrumentDisabled(t *testing.T) {
+	c := NewZkBNBClient("http://localhost").(*l2Client)
+	assert.Nil(t, c.tracer)
+	assert.Nil(t, c.metrics)
+	assert.Empty(t, c.interceptors)
+
+	ctx, end := startSpan(context.Background(), c.tracer, "zkbnb.Transfer")
+	assert.Equal(t, context.Background(), ctx)
+	end(nil)
+	c.metrics.observeTxSent(types.TxTypeTransfer, nil)
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Request is a logical call to the ZkBNB api made by the l2 client.
+type Request struct {
+	// Operation is the name of the client method making the call, e.g. GetAccountByName or SendRawTx
+	Operation string
+	// Method is the http method, GET or POST
+	Method string
+	// Path is the api path, e.g. /api/v1/account
+	Path string
+	// Params are the query params of a GET request, or the form values of a POST request
+	Params url.Values
+	// Header is sent with the request, it contains the headers set by WithHeader
+	Header http.Header
+}
+
+// Response is the raw response of a call.
+type Response struct {
+	// StatusCode is the http status code
+	StatusCode int
+	// Body is the raw response body
+	Body []byte
+	// Latency is the time the call took, including the retries
+	Latency time.Duration
+}
+
+// Invoker makes a call and returns its response. A response with a non 200 status is returned together
+// with an *APIError.
+type Invoker func(ctx context.Context, req *Request) (*Response, error)
+
+// ErrNoResponse is returned when a call ends with neither a response nor an error, e.g. an interceptor
+// short-circuits the call without a response.
+var ErrNoResponse = errors.New("no response")
+
+// Interceptor is a middleware around every api call of the l2 client, it calls next to continue the call.
+// An interceptor may change the request before calling next, or return a response without calling next
+// to short-circuit the call, e.g. to serve it from a cache. It must return a response or an error.
+type Interceptor func(ctx context.Context, req *Request, next Invoker) (*Response, error)
+
+// WithInterceptors adds interceptors to the client, the first one is the outermost.
+func WithInterceptors(interceptors ...Interceptor) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.interceptors = append(o.interceptors, interceptors...)
+	}
+}
+
+func chainInterceptors(interceptors []Interceptor, invoker Invoker) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, req *Request) (*Response, error) {
+			return interceptor(ctx, req, next)
+		}
+	}
+	return invoker
+}
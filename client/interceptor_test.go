@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterceptorsSeeOperation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "req-1", r.Header.Get("X-Request-Id"))
+		assert.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+		_, _ = w.Write([]byte(`{"index":3,"name":"sher.legend"}`))
+	}))
+	defer server.Close()
+
+	var order []string
+	var seen *Request
+	var seenResp *Response
+	outer := func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+		order = append(order, "outer")
+		seen = req
+		resp, err := next(ctx, req)
+		seenResp = resp
+		return resp, err
+	}
+	inner := func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+		order = append(order, "inner")
+		assert.Equal(t, "secret", req.Header.Get("X-Api-Key"))
+		req.Header.Set("X-Request-Id", "req-1")
+		return next(ctx, req)
+	}
+
+	c := NewZkBNBClient(server.URL, WithHeader("X-Api-Key", "secret"), WithInterceptors(outer, inner))
+	account, err := c.GetAccountByName("sher.legend")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), account.Index)
+
+	assert.Equal(t, []string{"outer", "inner"}, order)
+	assert.Equal(t, "GetAccountByName", seen.Operation)
+	assert.Equal(t, http.MethodGet, seen.Method)
+	assert.Equal(t, "/api/v1/account", seen.Path)
+	assert.Equal(t, "sher.legend", seen.Params.Get("value"))
+	assert.Equal(t, http.StatusOK, seenResp.StatusCode)
+	assert.Equal(t, `{"index":3,"name":"sher.legend"}`, string(seenResp.Body))
+	assert.True(t, seenResp.Latency > 0)
+}
+
+func TestInterceptorShortCircuit(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = w.Write([]byte(`{"index":1}`))
+	}))
+	defer server.Close()
+
+	cache := map[string][]byte{}
+	cached := func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+		key := req.Operation + "?" + req.Params.Encode()
+		if body, ok := cache[key]; ok {
+			return &Response{StatusCode: http.StatusOK, Body: body}, nil
+		}
+		resp, err := next(ctx, req)
+		if err == nil {
+			cache[key] = resp.Body
+		}
+		return resp, err
+	}
+
+	c := NewZkBNBClient(server.URL, WithInterceptors(cached))
+	for i := 0; i < 3; i++ {
+		gasAccount, err := c.GetGasAccount()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), gasAccount.Index)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestInterceptorShortCircuitWithoutResponse(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = w.Write([]byte(`{"index":1}`))
+	}))
+	defer server.Close()
+
+	drop := func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+		return nil, nil
+	}
+
+	c := NewZkBNBClient(server.URL, WithInterceptors(drop))
+	_, err := c.GetGasAccount()
+	assert.ErrorIs(t, err, ErrNoResponse)
+	_, err = c.SendRawTx(4, "{}")
+	assert.ErrorIs(t, err, ErrNoResponse)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+}
+
+func TestInterceptorShortCircuitErrorResponse(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = w.Write([]byte(`{"tx_hash":"hash"}`))
+	}))
+	defer server.Close()
+
+	reject := func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+		return &Response{StatusCode: http.StatusBadRequest, Body: []byte(`{"code":21010,"message":"invalid nonce"}`)}, nil
+	}
+
+	c := NewZkBNBClient(server.URL, WithInterceptors(reject))
+	_, err := c.SendRawTx(4, "{}")
+	assert.ErrorIs(t, err, ErrInvalidNonce)
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, "/api/v1/sendTx", apiErr.Path)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+}
+
+func TestInterceptorSeesErrorResponse(t *testing.T) {
+	server := newErrorServer(http.StatusBadRequest, `{"code":21010,"message":"invalid nonce"}`)
+	defer server.Close()
+
+	var status int
+	var operation string
+	observe := func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+		operation = req.Operation
+		resp, err := next(ctx, req)
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		return resp, err
+	}
+
+	c := NewZkBNBClient(server.URL, WithInterceptors(observe))
+	_, err := c.SendRawTx(4, "{}")
+	assert.ErrorIs(t, err, ErrInvalidNonce)
+	assert.Equal(t, "SendRawTx", operation)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
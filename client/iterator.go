@@ -0,0 +1,321 @@
+package client
+
+import (
+	"context"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+const defaultPageSize = 50
+
+type iteratorOption struct {
+	pageSize  uint32
+	txOptions []GetTxOptionFunc
+}
+
+type IteratorOptionFunc func(*iteratorOption)
+
+// IteratorWithPageSize sets the number of items fetched by each api call, 50 by default
+func IteratorWithPageSize(pageSize uint32) IteratorOptionFunc {
+	return func(o *iteratorOption) {
+		o.pageSize = pageSize
+	}
+}
+
+// IteratorWithTxOptions filters the txs of IterateTxsByAccountIndex and IterateExecutedTxs, e.g. by tx types
+func IteratorWithTxOptions(options ...GetTxOptionFunc) IteratorOptionFunc {
+	return func(o *iteratorOption) {
+		o.txOptions = append(o.txOptions, options...)
+	}
+}
+
+func newIteratorOption(options []IteratorOptionFunc) *iteratorOption {
+	opt := &iteratorOption{pageSize: defaultPageSize}
+	for _, f := range options {
+		f(opt)
+	}
+	if opt.pageSize == 0 {
+		opt.pageSize = defaultPageSize
+	}
+	return opt
+}
+
+// pageFetcher fetches limit items starting at offset and the total number of items in the list.
+// The offsets and limits are int64 in the pager, which holds both the int64 and the uint32 offsets of the list
+// apis. The list apis keep their types as they are part of ZkBNBQuerier, changing them would break the callers.
+type pageFetcher[T any] func(ctx context.Context, offset, limit int64) (total int64, items []T, err error)
+
+// pager walks a list page by page. Items shift between pages when the list changes during the walk:
+// the items of the current and previous pages are skipped when they show up again, and the offset is moved back
+// when the list shrinks so that no item is missed. The offset is not moved back before the current page, as the keys
+// of the items before it are not kept, so items can be missed when more items than the current page are removed
+// before the offset.
+type pager[T any, K comparable] struct {
+	fetch    pageFetcher[T]
+	key      func(T) K
+	pageSize int64
+
+	page    []T
+	pos     int
+	offset  int64
+	total   int64
+	fetched bool
+	done    bool
+	// seen and prevSeen are the keys of the items of the current and previous pages, pageStart is the offset
+	// the current page was fetched at
+	seen      map[K]struct{}
+	prevSeen  map[K]struct{}
+	pageStart int64
+	current   T
+	err       error
+}
+
+func newPager[T any, K comparable](pageSize uint32, fetch pageFetcher[T], key func(T) K) *pager[T, K] {
+	return &pager[T, K]{
+		fetch:    fetch,
+		key:      key,
+		pageSize: int64(pageSize),
+		seen:     make(map[K]struct{}),
+	}
+}
+
+func (p *pager[T, K]) next(ctx context.Context) bool {
+	for !p.done && p.err == nil {
+		for p.pos < len(p.page) {
+			item := p.page[p.pos]
+			p.pos++
+			k := p.key(item)
+			if _, ok := p.seen[k]; ok {
+				continue
+			}
+			// the keys of the items returned from the previous page are kept too, in case this page is fetched again
+			p.seen[k] = struct{}{}
+			if _, ok := p.prevSeen[k]; ok {
+				continue
+			}
+			p.current = item
+			return true
+		}
+
+		if p.fetched && p.offset >= p.total {
+			p.done = true
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			p.err = err
+			break
+		}
+
+		offset := p.offset
+		total, items, err := p.fetch(ctx, offset, p.pageSize)
+		if err != nil {
+			p.err = err
+			break
+		}
+		if p.fetched && total < p.total {
+			// Items were removed, the ones not returned yet may have moved to the current page.
+			p.offset -= p.total - total
+			if p.offset < p.pageStart {
+				p.offset = p.pageStart
+			}
+			p.total = total
+			continue
+		}
+		p.fetched = true
+		p.total = total
+		p.page, p.pos = items, 0
+		p.prevSeen, p.seen = p.seen, make(map[K]struct{}, len(items))
+		p.pageStart = offset
+		p.offset = offset + int64(len(items))
+		if len(items) == 0 {
+			p.done = true
+		}
+	}
+
+	var zero T
+	p.current = zero
+	return false
+}
+
+// TxIterator iterates over a list of txs, fetching a page whenever the previous one is consumed.
+// The iteration can be stopped at any time by not calling Next anymore or by canceling its context.
+type TxIterator struct {
+	p *pager[*types.Tx, string]
+}
+
+// Next moves to the next tx and reports whether there is one, check Err when it returns false
+func (it *TxIterator) Next(ctx context.Context) bool {
+	return it.p.next(ctx)
+}
+
+// Tx returns the current tx
+func (it *TxIterator) Tx() *types.Tx {
+	return it.p.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *TxIterator) Err() error {
+	return it.p.err
+}
+
+func newTxIterator(opt *iteratorOption, fetch func(ctx context.Context, offset, limit uint32) (uint32, []*types.Tx, error)) *TxIterator {
+	return &TxIterator{p: newPager(opt.pageSize, func(ctx context.Context, offset, limit int64) (int64, []*types.Tx, error) {
+		total, txs, err := fetch(ctx, uint32(offset), uint32(limit))
+		return int64(total), txs, err
+	}, func(tx *types.Tx) string {
+		return tx.Hash
+	})}
+}
+
+// BlockIterator iterates over a list of blocks, fetching a page whenever the previous one is consumed.
+type BlockIterator struct {
+	p *pager[*types.Block, int64]
+}
+
+// Next moves to the next block and reports whether there is one, check Err when it returns false
+func (it *BlockIterator) Next(ctx context.Context) bool {
+	return it.p.next(ctx)
+}
+
+// Block returns the current block
+func (it *BlockIterator) Block() *types.Block {
+	return it.p.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *BlockIterator) Err() error {
+	return it.p.err
+}
+
+// AccountIterator iterates over a list of accounts, fetching a page whenever the previous one is consumed.
+type AccountIterator struct {
+	p *pager[*types.SimpleAccount, int64]
+}
+
+// Next moves to the next account and reports whether there is one, check Err when it returns false
+func (it *AccountIterator) Next(ctx context.Context) bool {
+	return it.p.next(ctx)
+}
+
+// Account returns the current account
+func (it *AccountIterator) Account() *types.SimpleAccount {
+	return it.p.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *AccountIterator) Err() error {
+	return it.p.err
+}
+
+// AssetIterator iterates over a list of assets, fetching a page whenever the previous one is consumed.
+type AssetIterator struct {
+	p *pager[*types.Asset, uint32]
+}
+
+// Next moves to the next asset and reports whether there is one, check Err when it returns false
+func (it *AssetIterator) Next(ctx context.Context) bool {
+	return it.p.next(ctx)
+}
+
+// Asset returns the current asset
+func (it *AssetIterator) Asset() *types.Asset {
+	return it.p.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *AssetIterator) Err() error {
+	return it.p.err
+}
+
+// NftIterator iterates over a list of nfts, fetching a page whenever the previous one is consumed.
+type NftIterator struct {
+	p *pager[*types.Nft, int64]
+}
+
+// Next moves to the next nft and reports whether there is one, check Err when it returns false
+func (it *NftIterator) Next(ctx context.Context) bool {
+	return it.p.next(ctx)
+}
+
+// Nft returns the current nft
+func (it *NftIterator) Nft() *types.Nft {
+	return it.p.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *NftIterator) Err() error {
+	return it.p.err
+}
+
+func (c *l2Client) IterateTxs(options ...IteratorOptionFunc) *TxIterator {
+	opt := newIteratorOption(options)
+	return newTxIterator(opt, c.GetTxsWithContext)
+}
+
+func (c *l2Client) IterateTxsByAccountIndex(accountIndex int64, options ...IteratorOptionFunc) *TxIterator {
+	opt := newIteratorOption(options)
+	return newTxIterator(opt, func(ctx context.Context, offset, limit uint32) (uint32, []*types.Tx, error) {
+		return c.GetTxsByAccountIndexWithContext(ctx, accountIndex, offset, limit, opt.txOptions...)
+	})
+}
+
+func (c *l2Client) IteratePendingTxs(options ...IteratorOptionFunc) *TxIterator {
+	opt := newIteratorOption(options)
+	return newTxIterator(opt, c.GetPendingTxsWithContext)
+}
+
+func (c *l2Client) IterateExecutedTxs(options ...IteratorOptionFunc) *TxIterator {
+	opt := newIteratorOption(options)
+	return newTxIterator(opt, func(ctx context.Context, offset, limit uint32) (uint32, []*types.Tx, error) {
+		return c.GetExecutedTxsWithContext(ctx, offset, limit, opt.txOptions...)
+	})
+}
+
+func (c *l2Client) IterateBlocks(options ...IteratorOptionFunc) *BlockIterator {
+	opt := newIteratorOption(options)
+	return &BlockIterator{p: newPager(opt.pageSize, func(ctx context.Context, offset, limit int64) (int64, []*types.Block, error) {
+		total, blocks, err := c.GetBlocksWithContext(ctx, offset, limit)
+		return int64(total), blocks, err
+	}, func(block *types.Block) int64 {
+		return block.Height
+	})}
+}
+
+func (c *l2Client) IterateAccounts(options ...IteratorOptionFunc) *AccountIterator {
+	opt := newIteratorOption(options)
+	return &AccountIterator{p: newPager(opt.pageSize, func(ctx context.Context, offset, limit int64) (int64, []*types.SimpleAccount, error) {
+		accounts, err := c.GetAccountsWithContext(ctx, uint32(offset), uint32(limit))
+		if err != nil {
+			return 0, nil, err
+		}
+		return int64(accounts.Total), accounts.Accounts, nil
+	}, func(account *types.SimpleAccount) int64 {
+		return account.Index
+	})}
+}
+
+func (c *l2Client) IterateAssets(options ...IteratorOptionFunc) *AssetIterator {
+	opt := newIteratorOption(options)
+	return &AssetIterator{p: newPager(opt.pageSize, func(ctx context.Context, offset, limit int64) (int64, []*types.Asset, error) {
+		assets, err := c.GetAssetsWithContext(ctx, uint32(offset), uint32(limit))
+		if err != nil {
+			return 0, nil, err
+		}
+		return int64(assets.Total), assets.Assets, nil
+	}, func(asset *types.Asset) uint32 {
+		return asset.Id
+	})}
+}
+
+func (c *l2Client) IterateNftsByAccountIndex(accountIndex int64, options ...IteratorOptionFunc) *NftIterator {
+	opt := newIteratorOption(options)
+	return &NftIterator{p: newPager(opt.pageSize, func(ctx context.Context, offset, limit int64) (int64, []*types.Nft, error) {
+		nfts, err := c.GetNftsByAccountIndexWithContext(ctx, accountIndex, offset, limit)
+		if err != nil {
+			return 0, nil, err
+		}
+		return nfts.Total, nfts.Nfts, nil
+	}, func(nft *types.Nft) int64 {
+		return nft.Index
+	})}
+}
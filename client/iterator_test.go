@@ -0,0 +1,226 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// listServer serves txs and blocks pages of a list which can change between two requests.
+type listServer struct {
+	*httptest.Server
+	mu        sync.Mutex
+	items     []int64
+	requests  []pageRequest
+	afterPage func(s *listServer)
+}
+
+type pageRequest struct {
+	offset, limit int
+	types         string
+}
+
+func newListServer(t *testing.T, n int) *listServer {
+	s := &listServer{}
+	for i := 0; i < n; i++ {
+		s.items = append(s.items, int64(i))
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		q := r.URL.Query()
+		offset, err := strconv.Atoi(q.Get("offset"))
+		assert.NoError(t, err)
+		limit, err := strconv.Atoi(q.Get("limit"))
+		assert.NoError(t, err)
+		s.requests = append(s.requests, pageRequest{offset: offset, limit: limit, types: q.Get("types")})
+
+		var page []int64
+		if offset < len(s.items) {
+			end := offset + limit
+			if end > len(s.items) {
+				end = len(s.items)
+			}
+			page = s.items[offset:end]
+		}
+		var body interface{}
+		switch r.URL.Path {
+		case "/api/v1/blocks":
+			blocks := &types.Blocks{Total: uint32(len(s.items))}
+			for _, item := range page {
+				blocks.Blocks = append(blocks.Blocks, &types.Block{Height: item})
+			}
+			body = blocks
+		case "/api/v1/accountNfts":
+			nfts := &types.Nfts{Total: int64(len(s.items))}
+			for _, item := range page {
+				nfts.Nfts = append(nfts.Nfts, &types.Nft{Index: item})
+			}
+			body = nfts
+		default:
+			txs := &types.Txs{Total: uint32(len(s.items))}
+			for _, item := range page {
+				txs.Txs = append(txs.Txs, &types.Tx{Hash: fmt.Sprintf("tx-%d", item)})
+			}
+			body = txs
+		}
+		_ = json.NewEncoder(w).Encode(body)
+		if s.afterPage != nil {
+			s.afterPage(s)
+		}
+	}))
+	return s
+}
+
+func collectTxs(t *testing.T, it *TxIterator) []string {
+	var hashes []string
+	for it.Next(context.Background()) {
+		hashes = append(hashes, it.Tx().Hash)
+	}
+	assert.NoError(t, it.Err())
+	return hashes
+}
+
+func TestIterateBlocks(t *testing.T) {
+	server := newListServer(t, 7)
+	defer server.Close()
+
+	c := NewZkBNBClient(server.URL)
+	it := c.IterateBlocks(IteratorWithPageSize(3))
+	var heights []int64
+	for it.Next(context.Background()) {
+		heights = append(heights, it.Block().Height)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []int64{0, 1, 2, 3, 4, 5, 6}, heights)
+	assert.Equal(t, []pageRequest{{0, 3, ""}, {3, 3, ""}, {6, 3, ""}}, server.requests)
+	assert.False(t, it.Next(context.Background()))
+	assert.Nil(t, it.Block())
+}
+
+func TestIterateNfts(t *testing.T) {
+	server := newListServer(t, 2)
+	defer server.Close()
+
+	c := NewZkBNBClient(server.URL)
+	it := c.IterateNftsByAccountIndex(2)
+	var indexes []int64
+	for it.Next(context.Background()) {
+		indexes = append(indexes, it.Nft().Index)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []int64{0, 1}, indexes)
+	assert.Equal(t, []pageRequest{{0, defaultPageSize, ""}}, server.requests)
+}
+
+func TestIterateTxsGrowingList(t *testing.T) {
+	server := newListServer(t, 5)
+	defer server.Close()
+	// new txs are listed first, the txs already returned are pushed to the next page
+	server.afterPage = func(s *listServer) {
+		if len(s.requests) == 1 {
+			s.items = append([]int64{100, 101}, s.items...)
+		}
+	}
+
+	c := NewZkBNBClient(server.URL)
+	hashes := collectTxs(t, c.IterateTxs(IteratorWithPageSize(2)))
+	assert.Equal(t, []string{"tx-0", "tx-1", "tx-2", "tx-3", "tx-4"}, hashes)
+}
+
+func TestIteratePendingTxsShrinkingList(t *testing.T) {
+	server := newListServer(t, 6)
+	defer server.Close()
+	// the first pending txs get executed, the remaining ones move to the previous page
+	server.afterPage = func(s *listServer) {
+		if len(s.requests) == 1 {
+			s.items = s.items[2:]
+		}
+	}
+
+	c := NewZkBNBClient(server.URL)
+	hashes := collectTxs(t, c.IteratePendingTxs(IteratorWithPageSize(3)))
+	assert.Equal(t, []string{"tx-0", "tx-1", "tx-2", "tx-3", "tx-4", "tx-5"}, hashes)
+}
+
+func TestIteratePendingTxsRemovedAfterOffset(t *testing.T) {
+	server := newListServer(t, 12)
+	defer server.Close()
+	// pending txs after the offset get executed, the offset is moved back but no tx is returned twice
+	server.afterPage = func(s *listServer) {
+		if len(s.requests) == 2 {
+			s.items = append(s.items[:7:7], s.items[11:]...)
+		}
+	}
+
+	c := NewZkBNBClient(server.URL)
+	hashes := collectTxs(t, c.IteratePendingTxs(IteratorWithPageSize(3)))
+	assert.Equal(t, []string{"tx-0", "tx-1", "tx-2", "tx-3", "tx-4", "tx-5", "tx-6", "tx-11"}, hashes)
+}
+
+func TestPagerKeepsTwoPagesOfKeys(t *testing.T) {
+	p := newPager[int64, int64](10, func(ctx context.Context, offset, limit int64) (int64, []int64, error) {
+		var items []int64
+		for i := offset; i < offset+limit && i < 1000; i++ {
+			items = append(items, i)
+		}
+		return 1000, items, nil
+	}, func(item int64) int64 { return item })
+
+	n := 0
+	for p.next(context.Background()) {
+		assert.Equal(t, int64(n), p.current)
+		assert.LessOrEqual(t, len(p.seen)+len(p.prevSeen), 20)
+		n++
+	}
+	assert.NoError(t, p.err)
+	assert.Equal(t, 1000, n)
+}
+
+func TestIterateTxsWithTypes(t *testing.T) {
+	server := newListServer(t, 1)
+	defer server.Close()
+
+	c := NewZkBNBClient(server.URL)
+	hashes := collectTxs(t, c.IterateTxsByAccountIndex(2, IteratorWithTxOptions(GetTxWithTypes([]int64{types.TxTypeTransfer}))))
+	assert.Equal(t, []string{"tx-0"}, hashes)
+	assert.Equal(t, "[4]", server.requests[0].types)
+}
+
+func TestIteratorEarlyTermination(t *testing.T) {
+	server := newListServer(t, 10)
+	defer server.Close()
+
+	c := NewZkBNBClient(server.URL)
+	it := c.IterateTxsByAccountIndex(2, IteratorWithPageSize(4))
+	for i := 0; i < 2; i++ {
+		assert.True(t, it.Next(context.Background()))
+	}
+	assert.Len(t, server.requests, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for it.Next(ctx) {
+	}
+	assert.ErrorIs(t, it.Err(), context.Canceled)
+	assert.Len(t, server.requests, 1)
+}
+
+func TestIteratorError(t *testing.T) {
+	server := newErrorServer(http.StatusInternalServerError, `{"code":50000,"message":"internal error"}`)
+	defer server.Close()
+
+	c := NewZkBNBClient(server.URL)
+	it := c.IterateAccounts()
+	assert.False(t, it.Next(context.Background()))
+	assert.ErrorIs(t, it.Err(), ErrInternal)
+}
@@ -5,19 +5,26 @@ import (
 	"crypto/ecdsa"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/bnb-chain/zkbnb-go-sdk/client/abi"
+	"github.com/bnb-chain/zkbnb-go-sdk/zns"
 )
 
 type l1Client struct {
 	bscClient             *ethclient.Client
 	zkbnbContractInstance *abi.ZkBNB
 	privateKey            *ecdsa.PrivateKey
+	tracer                trace.Tracer
+	metrics               *l1Metrics
 }
 
 func (c *l1Client) SetPrivateKey(pk string) error {
@@ -30,95 +37,79 @@ func (c *l1Client) SetPrivateKey(pk string) error {
 }
 
 func (c *l1Client) DepositBNB(accountName string, amount *big.Int) (common.Hash, error) {
-	opts, err := c.getTransactor(nil)
-	if err != nil {
-		return common.Hash{}, err
-	}
-
-	opts.Value = amount
-	tx, err := c.zkbnbContractInstance.DepositBNB(opts, accountName)
-	if err != nil {
-		return common.Hash{}, err
-	}
-	return tx.Hash(), nil
+	return c.transact("DepositBNB", amount, func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return c.zkbnbContractInstance.DepositBNB(opts, accountName)
+	})
 }
 
 func (c *l1Client) DepositBEP20(token common.Address, accountName string, amount *big.Int) (common.Hash, error) {
-	opts, err := c.getTransactor(nil)
-	if err != nil {
-		return common.Hash{}, err
-	}
-
-	tx, err := c.zkbnbContractInstance.DepositBEP20(opts, token, amount, accountName)
-	if err != nil {
-		return common.Hash{}, err
-	}
-	return tx.Hash(), nil
+	return c.transact("DepositBEP20", nil, func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return c.zkbnbContractInstance.DepositBEP20(opts, token, amount, accountName)
+	})
 }
 
 func (c *l1Client) DepositNft(nftL1Address common.Address, accountName string, nftL1TokenId *big.Int) (common.Hash, error) {
-	opts, err := c.getTransactor(nil)
-	if err != nil {
-		return common.Hash{}, err
-	}
-
-	tx, err := c.zkbnbContractInstance.DepositNft(opts, accountName, nftL1Address, nftL1TokenId)
-	if err != nil {
-		return common.Hash{}, err
-	}
-	return tx.Hash(), nil
+	return c.transact("DepositNft", nil, func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return c.zkbnbContractInstance.DepositNft(opts, accountName, nftL1Address, nftL1TokenId)
+	})
 }
 
 // TODO: need query the charge fee
 func (c *l1Client) RegisterZNS(name string, owner common.Address, value *big.Int, pubKeyX [32]byte, pubKeyY [32]byte) (common.Hash, error) {
-	opts, err := c.getTransactor(value)
-	if err != nil {
-		return common.Hash{}, err
-	}
-
-	tx, err := c.zkbnbContractInstance.RegisterZNS(opts, name, owner, pubKeyX, pubKeyY)
+	name, err := zns.Label(name)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return tx.Hash(), nil
+	return c.transact("RegisterZNS", value, func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return c.zkbnbContractInstance.RegisterZNS(opts, name, owner, pubKeyX, pubKeyY)
+	})
 }
 
 func (c *l1Client) RequestFullExit(accountName string, asset common.Address) (common.Hash, error) {
-	opts, err := c.getTransactor(nil)
-	if err != nil {
-		return common.Hash{}, err
-	}
-
-	tx, err := c.zkbnbContractInstance.RequestFullExit(opts, accountName, asset)
-	if err != nil {
-		return common.Hash{}, err
-	}
-	return tx.Hash(), nil
+	return c.transact("RequestFullExit", nil, func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return c.zkbnbContractInstance.RequestFullExit(opts, accountName, asset)
+	})
 }
 
 func (c *l1Client) RequestFullExitNft(accountName string, nftIndex uint32) (common.Hash, error) {
-	opts, err := c.getTransactor(nil)
+	return c.transact("RequestFullExitNft", nil, func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return c.zkbnbContractInstance.RequestFullExitNft(opts, accountName, nftIndex)
+	})
+}
+
+// transact sends a contract tx built by send, the tx is traced and measured as method.
+func (c *l1Client) transact(method string, value *big.Int, send func(opts *bind.TransactOpts) (*ethtypes.Transaction, error)) (txHash common.Hash, err error) {
+	ctx, end := startSpan(context.Background(), c.tracer, "zkbnb.l1."+method)
+	start := time.Now()
+	defer func() {
+		c.metrics.observeL1Tx(method, start, err)
+		if err == nil {
+			trace.SpanFromContext(ctx).SetAttributes(attribute.String("zkbnb.l1.tx_hash", txHash.Hex()))
+		}
+		end(err)
+	}()
+
+	opts, err := c.getTransactor(ctx, value)
 	if err != nil {
 		return common.Hash{}, err
 	}
-
-	tx, err := c.zkbnbContractInstance.RequestFullExitNft(opts, accountName, nftIndex)
+	tx, err := send(opts)
 	if err != nil {
 		return common.Hash{}, err
 	}
 	return tx.Hash(), nil
 }
 
-func (c *l1Client) getTransactor(value *big.Int) (*bind.TransactOpts, error) {
+func (c *l1Client) getTransactor(ctx context.Context, value *big.Int) (*bind.TransactOpts, error) {
 	if c.privateKey == nil {
 		return nil, fmt.Errorf("private key is not set")
 	}
 
-	nonce, err := c.bscClient.PendingNonceAt(context.Background(), getAddressFromPrivateKey(c.privateKey))
+	nonce, err := c.bscClient.PendingNonceAt(ctx, getAddressFromPrivateKey(c.privateKey))
 	if err != nil {
 		return nil, err
 	}
-	chainId, err := c.bscClient.ChainID(context.Background())
+	chainId, err := c.bscClient.ChainID(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -128,7 +119,7 @@ func (c *l1Client) getTransactor(value *big.Int) (*bind.TransactOpts, error) {
 		return nil, err
 	}
 
-	gasPrice, err := c.bscClient.SuggestGasPrice(context.Background())
+	gasPrice, err := c.bscClient.SuggestGasPrice(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -136,6 +127,7 @@ func (c *l1Client) getTransactor(value *big.Int) (*bind.TransactOpts, error) {
 	auth.Nonce = big.NewInt(int64(nonce))
 	auth.Value = value // in wei
 	auth.GasPrice = gasPrice
+	auth.Context = ctx
 	return auth, nil
 }
 
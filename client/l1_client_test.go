@@ -1,17 +1,19 @@
 package client
 
 import (
-	"fmt"
 	"math/big"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/bnb-chain/zkbnb-go-sdk/accounts"
+	"github.com/bnb-chain/zkbnb-go-sdk/zkbnbtest"
+	"github.com/bnb-chain/zkbnb-go-sdk/zns"
 )
 
-var l1Endpoint = "https://data-seed-prebsc-1-s1.binance.org:8545"
 var zkbnbContract = "0x308fC6afE1A0738C8BAD2cAf5255c47A051e000e"
 var l1PrivateKey = "acbaa269bd7573ff12361be4b97201aef019776ea13384681d4e5ba6a88367d9"
 var l1Address = "0x8b2C5A5744F42AA9269BaabDd05933a96D8EF911"
@@ -20,45 +22,102 @@ var l1Address = "0x8b2C5A5744F42AA9269BaabDd05933a96D8EF911"
 var l2KeyManager, _ = accounts.NewSeedKeyManager("30e1a3762ff9944e9a4ad79477b756ef0aff3d2af76f0f40a0c3ec6ca76cf24b")
 var l2Name = "walt.legend"
 
+// getL1Client returns a client with l1PrivateKey talking to a fake l1 node
+func getL1Client(t *testing.T) (ZkBNBL1Client, *zkbnbtest.L1Server) {
+	server := zkbnbtest.NewL1Server()
+	t.Cleanup(server.Close)
+
+	client, err := NewZkBNBL1Client(server.URL, zkbnbContract)
+	require.NoError(t, err)
+	require.NoError(t, client.SetPrivateKey(l1PrivateKey))
+	return client, server
+}
+
+// lastL1Tx returns the last tx received by the fake l1 node
+func lastL1Tx(t *testing.T, server *zkbnbtest.L1Server) *zkbnbtest.L1Tx {
+	txs := server.Txs()
+	require.NotEmpty(t, txs)
+	return txs[len(txs)-1]
+}
+
 func TestRegisterZNS(t *testing.T) {
-	client, _ := NewZkBNBL1Client(l1Endpoint, zkbnbContract)
-	err := client.SetPrivateKey(l1PrivateKey)
-	assert.NoError(t, err)
+	client, server := getL1Client(t)
 	pk := l2KeyManager.PubKeyPoint()
-	fmt.Println(pk[0])
-	fmt.Println(pk[1])
 	hash, err := client.RegisterZNS("walt", common.HexToAddress(l1Address), big.NewInt(1e17), pk[0], pk[1])
 	assert.NoError(t, err)
-	fmt.Println(hash)
+
+	tx := lastL1Tx(t, server)
+	assert.Equal(t, hash, tx.Tx.Hash())
+	assert.Equal(t, common.HexToAddress(zkbnbContract), *tx.Tx.To())
+	key, err := crypto.HexToECDSA(l1PrivateKey)
+	require.NoError(t, err)
+	assert.Equal(t, getAddressFromPrivateKey(key), tx.From)
+	assert.Equal(t, big.NewInt(1e17), tx.Tx.Value())
+	assert.Equal(t, "registerZNS", tx.Method)
+	assert.Equal(t, "walt", tx.Args["_name"])
+	assert.Equal(t, common.HexToAddress(l1Address), tx.Args["_owner"])
+	assert.Equal(t, pk[0], tx.Args["_pubKeyX"])
+}
+
+func TestRegisterZNSName(t *testing.T) {
+	client, server := getL1Client(t)
+	pk := l2KeyManager.PubKeyPoint()
+	_, err := client.RegisterZNS("Walt.legend", common.HexToAddress(l1Address), big.NewInt(1e17), pk[0], pk[1])
+	require.NoError(t, err)
+	assert.Equal(t, "walt", lastL1Tx(t, server).Args["_name"])
+
+	_, err = client.RegisterZNS("walt_1", common.HexToAddress(l1Address), big.NewInt(1e17), pk[0], pk[1])
+	assert.ErrorIs(t, err, zns.ErrInvalidCharacter)
+	_, err = client.RegisterZNS("walt.eth", common.HexToAddress(l1Address), big.NewInt(1e17), pk[0], pk[1])
+	assert.ErrorIs(t, err, zns.ErrInvalidSuffix)
+	assert.Len(t, server.Txs(), 1)
 }
 
 func TestDepositBNB(t *testing.T) {
-	client, _ := NewZkBNBL1Client(l1Endpoint, zkbnbContract)
-	err := client.SetPrivateKey(l1PrivateKey)
-	assert.NoError(t, err)
+	client, server := getL1Client(t)
 	hash, err := client.DepositBNB("walt", big.NewInt(1e18))
 	assert.NoError(t, err)
-	fmt.Println(hash)
+
+	tx := lastL1Tx(t, server)
+	assert.Equal(t, hash, tx.Tx.Hash())
+	assert.Equal(t, big.NewInt(1e18), tx.Tx.Value())
+	assert.Equal(t, "depositBNB", tx.Method)
 }
 
 func TestFullExitBNB(t *testing.T) {
-	client, _ := NewZkBNBL1Client(l1Endpoint, zkbnbContract)
-	err := client.SetPrivateKey(l1PrivateKey)
-	assert.NoError(t, err)
+	client, server := getL1Client(t)
 	hash, err := client.RequestFullExit("walt", common.HexToAddress("0x0000000000000000000000000000000000000000"))
 	assert.NoError(t, err)
-	fmt.Println(hash)
+
+	tx := lastL1Tx(t, server)
+	assert.Equal(t, hash, tx.Tx.Hash())
+	assert.Equal(t, "requestFullExit", tx.Method)
 }
 
 func TestDepositBep20(t *testing.T) {
 	assetPrivateKey := "dc3543c9c912db587693f9b27e4d221c367772cc905cbb4b76c9f30050d2534c"
 
-	client, _ := NewZkBNBL1Client(l1Endpoint, zkbnbContract)
-	client.SetPrivateKey(assetPrivateKey)
+	client, server := getL1Client(t)
+	require.NoError(t, client.SetPrivateKey(assetPrivateKey))
 	txHash, err := client.DepositBEP20(common.HexToAddress("0x92AC3dBcA5AA61e43bD74ef59F5f3acd1E724730"), "sher", big.NewInt(1000000))
-	if err != nil {
-		println(err.Error())
-		return
+	assert.NoError(t, err)
+
+	tx := lastL1Tx(t, server)
+	assert.Equal(t, txHash, tx.Tx.Hash())
+	assert.Equal(t, "depositBEP20", tx.Method)
+	assert.Equal(t, big.NewInt(1000000), tx.Args["_amount"])
+}
+
+func TestL1ClientNonces(t *testing.T) {
+	client, server := getL1Client(t)
+	for i := 0; i < 3; i++ {
+		_, err := client.DepositBNB("walt", big.NewInt(1e18))
+		assert.NoError(t, err)
+	}
+	txs := server.Txs()
+	if assert.Len(t, txs, 3) {
+		for i, tx := range txs {
+			assert.Equal(t, uint64(i), tx.Tx.Nonce())
+		}
 	}
-	println("deposit bep 20 success, tx hash=", txHash.String())
 }
@@ -1,9 +1,10 @@
 package client
 
 import (
-	"crypto/tls"
+	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -11,13 +12,17 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/bnb-chain/zkbnb-go-sdk/accounts"
 	"github.com/bnb-chain/zkbnb-go-sdk/txutils"
 	"github.com/bnb-chain/zkbnb-go-sdk/types"
+	"github.com/bnb-chain/zkbnb-go-sdk/zns"
 )
 
 const defaultExpireTime = time.Minute * 10
@@ -32,9 +37,11 @@ var (
 		MaxConnsPerHost:     1000,
 		MaxIdleConnsPerHost: 100,
 		IdleConnTimeout:     10 * time.Second,
-		TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
 	}
 
+	// HttpClient is the default http client of the l2 clients created without http options.
+	//
+	// Deprecated: use WithHttpClient or the other ClientOptionFunc to configure each client.
 	HttpClient = &http.Client{
 		Timeout:   time.Second * 10,
 		Transport: transport,
@@ -42,8 +49,29 @@ var (
 )
 
 type l2Client struct {
-	endpoint   string
-	keyManager accounts.KeyManager
+	endpoint     string
+	keyManager   accounts.KeyManager
+	httpClient   *http.Client
+	headers      http.Header
+	retryPolicy  *RetryPolicy
+	endpoints    *endpointPool
+	interceptors []Interceptor
+	tracer       trace.Tracer
+	metrics      *metrics
+	nonces       *NonceManager
+
+	// roundUpGasFee rounds unpackable gas fees up instead of failing, see WithGasFeeRoundUp
+	roundUpGasFee bool
+
+	// batchEndpoint sends the txs of SendTxs with the sendTxs api, see WithBatchEndpoint
+	batchEndpoint bool
+
+	// treasuryRate is the default treasury rate of the offers, see WithOfferTreasuryRate
+	treasuryRate int64
+
+	// offerIds are the last offer ids allocated by account index
+	offerIdsMu sync.Mutex
+	offerIds   map[int64]int64
 }
 
 func (c *l2Client) SetKeyManager(keyManager accounts.KeyManager) {
@@ -54,218 +82,319 @@ func (c *l2Client) KeyManager() accounts.KeyManager {
 	return c.keyManager
 }
 
-func (c *l2Client) GetCurrentHeight() (int64, error) {
-	resp, err := HttpClient.Get(c.endpoint + fmt.Sprintf("/api/v1/currentHeight"))
+// get sends a GET request to the api path and decodes the json response into result.
+// The query params are url encoded, params can be nil.
+func (c *l2Client) get(ctx context.Context, operation, path string, params url.Values, result interface{}) error {
+	return c.call(ctx, &Request{
+		Operation: operation,
+		Method:    http.MethodGet,
+		Path:      path,
+		Params:    params,
+	}, result)
+}
+
+// postForm sends a form encoded POST request to the api path and decodes the json response into result.
+func (c *l2Client) postForm(ctx context.Context, operation, path string, data url.Values, result interface{}) error {
+	return c.call(ctx, &Request{
+		Operation: operation,
+		Method:    http.MethodPost,
+		Path:      path,
+		Params:    data,
+	}, result)
+}
+
+// call passes the request through the interceptors and decodes the json response into result.
+func (c *l2Client) call(ctx context.Context, req *Request, result interface{}) error {
+	req.Header = c.headers.Clone()
+	if req.Header == nil {
+		req.Header = make(http.Header)
+	}
+	resp, err := chainInterceptors(c.interceptors, c.invoke)(ctx, req)
 	if err != nil {
-		return -1, err
+		return err
+	}
+	if resp == nil {
+		return fmt.Errorf("%s: %w", req.Operation, ErrNoResponse)
+	}
+	// an interceptor may answer with an error response without calling the server
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp.StatusCode, req.Path, resp.Body)
+	}
+	return json.Unmarshal(resp.Body, result)
+}
+
+// invoke sends the request, the GET requests are retried with the retry policy.
+// The POST requests are not retried here as resubmitting them is not always safe.
+func (c *l2Client) invoke(ctx context.Context, req *Request) (*Response, error) {
+	start := time.Now()
+	var resp *Response
+	call := func(attempt int) (err error) {
+		resp, err = c.invokeOnce(ctx, req)
+		return err
+	}
+	var err error
+	if req.Method == http.MethodGet {
+		err = c.retryPolicy.do(ctx, call)
+	} else {
+		err = call(0)
+	}
+	if resp != nil {
+		resp.Latency = time.Since(start)
+	}
+	return resp, err
+}
+
+func (c *l2Client) invokeOnce(ctx context.Context, req *Request) (resp *Response, err error) {
+	err = c.withEndpoint(ctx, req.Method == http.MethodGet, func(endpoint string) error {
+		httpReq, err := newHttpRequest(ctx, endpoint, req)
+		if err != nil {
+			return err
+		}
+		resp, err = c.do(httpReq)
+		return err
+	})
+	if err == nil && resp == nil {
+		// no endpoint was called
+		return nil, ErrNoResponse
+	}
+	return resp, err
+}
+
+func newHttpRequest(ctx context.Context, endpoint string, req *Request) (*http.Request, error) {
+	var httpReq *http.Request
+	var err error
+	if req.Method == http.MethodPost {
+		httpReq, err = http.NewRequestWithContext(ctx, http.MethodPost, endpoint+req.Path, strings.NewReader(req.Params.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		path := req.Path
+		if len(req.Params) > 0 {
+			path += "?" + req.Params.Encode()
+		}
+		httpReq, err = http.NewRequestWithContext(ctx, req.Method, endpoint+path, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for key, values := range req.Header {
+		for _, value := range values {
+			httpReq.Header.Add(key, value)
+		}
+	}
+	return httpReq, nil
+}
+
+func pageParams(offset, limit int64) url.Values {
+	return url.Values{
+		"offset": {strconv.FormatInt(offset, 10)},
+		"limit":  {strconv.FormatInt(limit, 10)},
+	}
+}
+
+// withEndpoint runs call with the endpoint, failing over between the replicas if the client has several
+// and failover is set.
+func (c *l2Client) withEndpoint(ctx context.Context, failover bool, call func(endpoint string) error) error {
+	if c.endpoints == nil {
+		return call(c.endpoint)
+	}
+	return c.endpoints.do(ctx, failover, call)
+}
+
+// getCurrentHeightAt returns the current height of a specific endpoint, it is used for health checks.
+func (c *l2Client) getCurrentHeightAt(ctx context.Context, endpoint string) (int64, error) {
+	req, err := newHttpRequest(ctx, endpoint, &Request{
+		Method: http.MethodGet,
+		Path:   "/api/v1/currentHeight",
+		Header: c.headers,
+	})
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return 0, err
+	}
+	result := &types.CurrentHeight{}
+	if err := json.Unmarshal(resp.Body, result); err != nil {
+		return 0, err
+	}
+	return result.Height, nil
+}
+
+// do sends the http request, a response with a non 200 status is returned together with an *APIError.
+func (c *l2Client) do(req *http.Request) (*Response, error) {
+	httpClient := c.httpClient
+	if httpClient == nil {
+		httpClient = HttpClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
 	}
 	defer resp.Body.Close()
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return -1, err
+		return nil, err
+	}
+	result := &Response{
+		StatusCode: resp.StatusCode,
+		Body:       body,
 	}
 	if resp.StatusCode != http.StatusOK {
-		return -1, fmt.Errorf(string(body))
+		return result, newAPIError(resp.StatusCode, req.URL.Path, body)
 	}
+	return result, nil
+}
+
+func (c *l2Client) GetCurrentHeight() (int64, error) {
+	return c.GetCurrentHeightWithContext(context.Background())
+}
+
+func (c *l2Client) GetCurrentHeightWithContext(ctx context.Context) (int64, error) {
 	result := &types.CurrentHeight{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, "GetCurrentHeight", "/api/v1/currentHeight", nil, result); err != nil {
 		return -1, err
 	}
 	return result.Height, nil
 }
 
 func (c *l2Client) GetTxsByAccountPk(accountPk string, offset, limit uint32, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error) {
+	return c.GetTxsByAccountPkWithContext(context.Background(), accountPk, offset, limit, options...)
+}
+
+func (c *l2Client) GetTxsByAccountPkWithContext(ctx context.Context, accountPk string, offset, limit uint32, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error) {
 	opt := &getTxOption{}
 	for _, f := range options {
 		f(opt)
 	}
 
-	path := fmt.Sprintf("/api/v1/accountTxs?by=account_pk&value=%s&offset=%d&limit=%d", accountPk, offset, limit)
-	if len(opt.Types) > 0 {
-		txTypes, _ := json.Marshal(opt.Types)
-		path += fmt.Sprintf("&types=%s", string(txTypes))
+	params := url.Values{
+		"by":     {"account_pk"},
+		"value":  {accountPk},
+		"offset": {strconv.FormatUint(uint64(offset), 10)},
+		"limit":  {strconv.FormatUint(uint64(limit), 10)},
 	}
+	opt.setTypes(params)
 
-	resp, err := HttpClient.Get(c.endpoint + path)
-	if err != nil {
-		return 0, nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, nil, fmt.Errorf(string(body))
-	}
 	result := &types.Txs{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, "GetTxsByAccountPk", "/api/v1/accountTxs", params, result); err != nil {
 		return 0, nil, err
 	}
 	return result.Total, result.Txs, nil
 }
 
 func (c *l2Client) GetTxsByAccountName(accountName string, offset, limit uint32, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error) {
+	return c.GetTxsByAccountNameWithContext(context.Background(), accountName, offset, limit, options...)
+}
+
+func (c *l2Client) GetTxsByAccountNameWithContext(ctx context.Context, accountName string, offset, limit uint32, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error) {
 	opt := &getTxOption{}
 	for _, f := range options {
 		f(opt)
 	}
 
-	path := fmt.Sprintf("/api/v1/accountTxs?by=account_name&value=%s&offset=%d&limit=%d", accountName, offset, limit)
-	if len(opt.Types) > 0 {
-		txTypes, _ := json.Marshal(opt.Types)
-		path += fmt.Sprintf("&types=%s", string(txTypes))
+	params := url.Values{
+		"by":     {"account_name"},
+		"value":  {accountName},
+		"offset": {strconv.FormatUint(uint64(offset), 10)},
+		"limit":  {strconv.FormatUint(uint64(limit), 10)},
 	}
+	opt.setTypes(params)
 
-	resp, err := HttpClient.Get(c.endpoint + path)
-	if err != nil {
-		return 0, nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, nil, fmt.Errorf(string(body))
-	}
 	result := &types.Txs{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, "GetTxsByAccountName", "/api/v1/accountTxs", params, result); err != nil {
 		return 0, nil, err
 	}
 	return result.Total, result.Txs, nil
 }
 
 func (c *l2Client) GetTxs(offset, limit uint32) (total uint32, txs []*types.Tx, err error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/txs?offset=%d&limit=%d", offset, limit))
-	if err != nil {
-		return 0, nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, nil, fmt.Errorf(string(body))
-	}
+	return c.GetTxsWithContext(context.Background(), offset, limit)
+}
+
+func (c *l2Client) GetTxsWithContext(ctx context.Context, offset, limit uint32) (total uint32, txs []*types.Tx, err error) {
 	result := &types.Txs{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, "GetTxs", "/api/v1/txs", pageParams(int64(offset), int64(limit)), result); err != nil {
 		return 0, nil, err
 	}
 	return result.Total, result.Txs, nil
 }
 
 func (c *l2Client) GetTxsByAccountIndex(accountIndex int64, offset, limit uint32, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error) {
+	return c.GetTxsByAccountIndexWithContext(context.Background(), accountIndex, offset, limit, options...)
+}
+
+func (c *l2Client) GetTxsByAccountIndexWithContext(ctx context.Context, accountIndex int64, offset, limit uint32, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error) {
 	opt := &getTxOption{}
 	for _, f := range options {
 		f(opt)
 	}
 
-	path := fmt.Sprintf("/api/v1/accountTxs?by=account_index&value=%d&offset=%d&limit=%d", accountIndex, offset, limit)
-	if len(opt.Types) > 0 {
-		txTypes, _ := json.Marshal(opt.Types)
-		path += fmt.Sprintf("&types=%s", string(txTypes))
+	params := url.Values{
+		"by":     {"account_index"},
+		"value":  {strconv.FormatInt(accountIndex, 10)},
+		"offset": {strconv.FormatUint(uint64(offset), 10)},
+		"limit":  {strconv.FormatUint(uint64(limit), 10)},
 	}
+	opt.setTypes(params)
 
-	resp, err := HttpClient.Get(c.endpoint + path)
-	if err != nil {
-		return 0, nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, nil, fmt.Errorf(string(body))
-	}
 	result := &types.Txs{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, "GetTxsByAccountIndex", "/api/v1/accountTxs", params, result); err != nil {
 		return 0, nil, err
 	}
 	return result.Total, result.Txs, nil
 }
 
 func (c *l2Client) Search(keyword string) (*types.Search, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/search?keyword=%s", keyword))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
+	return c.SearchWithContext(context.Background(), keyword)
+}
+
+func (c *l2Client) SearchWithContext(ctx context.Context, keyword string) (*types.Search, error) {
 	result := &types.Search{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, "Search", "/api/v1/search", url.Values{"keyword": {keyword}}, result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
 func (c *l2Client) GetAccounts(offset, limit uint32) (*types.Accounts, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/accounts?offset=%d&limit=%d", offset, limit))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
+	return c.GetAccountsWithContext(context.Background(), offset, limit)
+}
+
+func (c *l2Client) GetAccountsWithContext(ctx context.Context, offset, limit uint32) (*types.Accounts, error) {
 	result := &types.Accounts{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, "GetAccounts", "/api/v1/accounts", pageParams(int64(offset), int64(limit)), result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
 func (c *l2Client) GetGasFeeAssets() (*types.GasFeeAssets, error) {
-	resp, err := HttpClient.Get(c.endpoint + "/api/v1/gasFeeAssets")
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
+	return c.GetGasFeeAssetsWithContext(context.Background())
+}
+
+func (c *l2Client) GetGasFeeAssetsWithContext(ctx context.Context) (*types.GasFeeAssets, error) {
 	result := &types.GasFeeAssets{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, "GetGasFeeAssets", "/api/v1/gasFeeAssets", nil, result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
 func (c *l2Client) GetGasFee(assetId int64, txType int) (*big.Int, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/gasFee?asset_id=%d&tx_type=%d", assetId, txType))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
+	return c.GetGasFeeWithContext(context.Background(), assetId, txType)
+}
+
+func (c *l2Client) GetGasFeeWithContext(ctx context.Context, assetId int64, txType int) (*big.Int, error) {
 	result := &types.GasFee{}
-	if err := json.Unmarshal(body, result); err != nil {
+	params := url.Values{
+		"asset_id": {strconv.FormatInt(assetId, 10)},
+		"tx_type":  {strconv.Itoa(txType)},
+	}
+	if err := c.get(ctx, "GetGasFee", "/api/v1/gasFee", params, result); err != nil {
 		return nil, err
 	}
 	var price big.Int
@@ -274,443 +403,325 @@ func (c *l2Client) GetGasFee(assetId int64, txType int) (*big.Int, error) {
 }
 
 func (c *l2Client) GetAssetById(id uint32) (*types.Asset, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/asset?by=id&value=%d", id))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
+	return c.GetAssetByIdWithContext(context.Background(), id)
+}
+
+func (c *l2Client) GetAssetByIdWithContext(ctx context.Context, id uint32) (*types.Asset, error) {
 	result := &types.Asset{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, "GetAssetById", "/api/v1/asset", url.Values{"by": {"id"}, "value": {strconv.FormatUint(uint64(id), 10)}}, result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
 func (c *l2Client) GetAssetBySymbol(symbol string) (*types.Asset, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/asset?by=symbol&value=%s", symbol))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
+	return c.GetAssetBySymbolWithContext(context.Background(), symbol)
+}
+
+func (c *l2Client) GetAssetBySymbolWithContext(ctx context.Context, symbol string) (*types.Asset, error) {
 	result := &types.Asset{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, "GetAssetBySymbol", "/api/v1/asset", url.Values{"by": {"symbol"}, "value": {symbol}}, result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
 func (c *l2Client) GetAssets(offset, limit uint32) (*types.Assets, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/assets?offset=%d&limit=%d", offset, limit))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
+	return c.GetAssetsWithContext(context.Background(), offset, limit)
+}
+
+func (c *l2Client) GetAssetsWithContext(ctx context.Context, offset, limit uint32) (*types.Assets, error) {
 	result := &types.Assets{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, "GetAssets", "/api/v1/assets", pageParams(int64(offset), int64(limit)), result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
 func (c *l2Client) GetLayer2BasicInfo() (*types.Layer2BasicInfo, error) {
-	resp, err := HttpClient.Get(c.endpoint + "/api/v1/layer2BasicInfo")
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
+	return c.GetLayer2BasicInfoWithContext(context.Background())
+}
+
+func (c *l2Client) GetLayer2BasicInfoWithContext(ctx context.Context) (*types.Layer2BasicInfo, error) {
 	result := &types.Layer2BasicInfo{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, "GetLayer2BasicInfo", "/api/v1/layer2BasicInfo", nil, result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
 func (c *l2Client) GetBlockByCommitment(blockCommitment string) (*types.Block, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/block?by=commitment&value=%s", blockCommitment))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
+	return c.GetBlockByCommitmentWithContext(context.Background(), blockCommitment)
+}
+
+func (c *l2Client) GetBlockByCommitmentWithContext(ctx context.Context, blockCommitment string) (*types.Block, error) {
 	result := &types.Block{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, "GetBlockByCommitment", "/api/v1/block", url.Values{"by": {"commitment"}, "value": {blockCommitment}}, result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
 func (c *l2Client) GetAccountByIndex(accountIndex int64) (*types.Account, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/account?by=index&value=%d", accountIndex))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
+	return c.GetAccountByIndexWithContext(context.Background(), accountIndex)
+}
+
+func (c *l2Client) GetAccountByIndexWithContext(ctx context.Context, accountIndex int64) (*types.Account, error) {
 	result := &types.Account{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, "GetAccountByIndex", "/api/v1/account", url.Values{"by": {"index"}, "value": {strconv.FormatInt(accountIndex, 10)}}, result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
 func (c *l2Client) GetAccountByPk(accountPk string) (*types.Account, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/account?by=pk&value=%s", accountPk))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
+	return c.GetAccountByPkWithContext(context.Background(), accountPk)
+}
+
+func (c *l2Client) GetAccountByPkWithContext(ctx context.Context, accountPk string) (*types.Account, error) {
 	result := &types.Account{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, "GetAccountByPk", "/api/v1/account", url.Values{"by": {"pk"}, "value": {accountPk}}, result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
 func (c *l2Client) GetTx(hash string) (*types.EnrichedTx, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/tx?hash=%s", hash))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
+	return c.GetTxWithContext(context.Background(), hash)
+}
+
+func (c *l2Client) GetTxWithContext(ctx context.Context, hash string) (*types.EnrichedTx, error) {
 	txResp := &types.EnrichedTx{}
-	if err := json.Unmarshal(body, txResp); err != nil {
+	if err := c.get(withStickyTx(ctx, hash), "GetTx", "/api/v1/tx", url.Values{"hash": {hash}}, txResp); err != nil {
 		return nil, err
 	}
 	return txResp, nil
 }
 
 func (c *l2Client) GetPendingTxs(offset, limit uint32) (total uint32, txs []*types.Tx, err error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/pendingTxs?offset=%d&limit=%d", offset, limit))
-	if err != nil {
-		return 0, nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, nil, fmt.Errorf(string(body))
-	}
+	return c.GetPendingTxsWithContext(context.Background(), offset, limit)
+}
+
+func (c *l2Client) GetPendingTxsWithContext(ctx context.Context, offset, limit uint32) (total uint32, txs []*types.Tx, err error) {
 	txsResp := &types.Txs{}
-	if err := json.Unmarshal(body, txsResp); err != nil {
+	if err := c.get(ctx, "GetPendingTxs", "/api/v1/pendingTxs", pageParams(int64(offset), int64(limit)), txsResp); err != nil {
 		return 0, nil, err
 	}
 	return txsResp.Total, txsResp.Txs, nil
 }
 
 func (c *l2Client) GetPendingTxsByAccountName(accountName string, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error) {
+	return c.GetPendingTxsByAccountNameWithContext(context.Background(), accountName, options...)
+}
+
+func (c *l2Client) GetPendingTxsByAccountNameWithContext(ctx context.Context, accountName string, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error) {
 	opt := &getTxOption{}
 	for _, f := range options {
 		f(opt)
 	}
 
-	path := "/api/v1/accountPendingTxs?by=account_name&value=" + accountName
-	if len(opt.Types) > 0 {
-		txTypes, _ := json.Marshal(opt.Types)
-		path += fmt.Sprintf("&types=%s", string(txTypes))
+	params := url.Values{
+		"by":    {"account_name"},
+		"value": {accountName},
 	}
+	opt.setTypes(params)
 
-	resp, err := HttpClient.Get(c.endpoint + path)
-	if err != nil {
-		return 0, nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, nil, fmt.Errorf(string(body))
-	}
 	txsResp := &types.Txs{}
-	if err := json.Unmarshal(body, txsResp); err != nil {
+	if err := c.get(ctx, "GetPendingTxsByAccountName", "/api/v1/accountPendingTxs", params, txsResp); err != nil {
 		return 0, nil, err
 	}
 	return txsResp.Total, txsResp.Txs, nil
 }
 
 func (c *l2Client) GetExecutedTxs(offset, limit uint32, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error) {
+	return c.GetExecutedTxsWithContext(context.Background(), offset, limit, options...)
+}
+
+func (c *l2Client) GetExecutedTxsWithContext(ctx context.Context, offset, limit uint32, options ...GetTxOptionFunc) (total uint32, txs []*types.Tx, err error) {
 	opt := &getTxOption{}
 	for _, f := range options {
 		f(opt)
 	}
 
-	path := fmt.Sprintf("/api/v1/executedTxs?offset=%d&limit=%d", offset, limit)
+	params := pageParams(int64(offset), int64(limit))
 	if len(opt.FromHash) > 0 {
-		path += fmt.Sprintf("&from_hash=%s", opt.FromHash)
+		params.Set("from_hash", opt.FromHash)
 	}
 
-	resp, err := HttpClient.Get(c.endpoint + path)
-	if err != nil {
-		return 0, nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, nil, fmt.Errorf(string(body))
-	}
 	txsResp := &types.Txs{}
-	if err := json.Unmarshal(body, txsResp); err != nil {
+	if err := c.get(ctx, "GetExecutedTxs", "/api/v1/executedTxs", params, txsResp); err != nil {
 		return 0, nil, err
 	}
 	return txsResp.Total, txsResp.Txs, nil
 }
 
 func (c *l2Client) GetAccountByName(accountName string) (*types.Account, error) {
-	resp, err := HttpClient.Get(c.endpoint + "/api/v1/account?by=name&value=" + accountName)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
+	return c.GetAccountByNameWithContext(context.Background(), accountName)
+}
+
+func (c *l2Client) GetAccountByNameWithContext(ctx context.Context, accountName string) (*types.Account, error) {
 	account := &types.Account{}
-	if err := json.Unmarshal(body, account); err != nil {
+	if err := c.get(ctx, "GetAccountByName", "/api/v1/account", url.Values{"by": {"name"}, "value": {accountName}}, account); err != nil {
 		return nil, err
 	}
 	return account, nil
 }
 
 func (c *l2Client) GetNextNonce(accountIdx int64) (int64, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/nextNonce?account_index=%d", accountIdx))
-	if err != nil {
-		return 0, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf(string(body))
-	}
+	return c.GetNextNonceWithContext(context.Background(), accountIdx)
+}
+
+func (c *l2Client) GetNextNonceWithContext(ctx context.Context, accountIdx int64) (int64, error) {
 	result := &types.NextNonce{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, "GetNextNonce", "/api/v1/nextNonce", url.Values{"account_index": {strconv.FormatInt(accountIdx, 10)}}, result); err != nil {
 		return 0, err
 	}
 	return int64(result.Nonce), nil
 }
 
 func (c *l2Client) GetTxsByBlockHeight(blockHeight uint32) ([]*types.Tx, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/blockTxs?by=block_height&value=%d", blockHeight))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
+	return c.GetTxsByBlockHeightWithContext(context.Background(), blockHeight)
+}
+
+func (c *l2Client) GetTxsByBlockHeightWithContext(ctx context.Context, blockHeight uint32) ([]*types.Tx, error) {
 	result := &types.Txs{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, "GetTxsByBlockHeight", "/api/v1/blockTxs", url.Values{"by": {"block_height"}, "value": {strconv.FormatUint(uint64(blockHeight), 10)}}, result); err != nil {
 		return nil, err
 	}
 	return result.Txs, nil
 }
 
 func (c *l2Client) GetMaxOfferId(accountIndex int64) (uint64, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/maxOfferId?account_index=%d", accountIndex))
-	if err != nil {
-		return 0, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf(string(body))
-	}
+	return c.GetMaxOfferIdWithContext(context.Background(), accountIndex)
+}
+
+func (c *l2Client) GetMaxOfferIdWithContext(ctx context.Context, accountIndex int64) (uint64, error) {
 	result := &types.MaxOfferId{}
-	if err := json.Unmarshal(body, result); err != nil {
+	if err := c.get(ctx, "GetMaxOfferId", "/api/v1/maxOfferId", url.Values{"account_index": {strconv.FormatInt(accountIndex, 10)}}, result); err != nil {
 		return 0, err
 	}
 	return result.OfferId, nil
 }
 
 func (c *l2Client) GetBlockByHeight(blockHeight int64) (*types.Block, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/block?by=height&value=%d", blockHeight))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
+	return c.GetBlockByHeightWithContext(context.Background(), blockHeight)
+}
+
+func (c *l2Client) GetBlockByHeightWithContext(ctx context.Context, blockHeight int64) (*types.Block, error) {
 	res := &types.Block{}
-	if err := json.Unmarshal(body, res); err != nil {
+	if err := c.get(ctx, "GetBlockByHeight", "/api/v1/block", url.Values{"by": {"height"}, "value": {strconv.FormatInt(blockHeight, 10)}}, res); err != nil {
 		return nil, err
 	}
 	return res, nil
 }
 
 func (c *l2Client) GetBlocks(offset, limit int64) (uint32, []*types.Block, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/blocks?limit=%d&offset=%d", limit, offset))
-	if err != nil {
-		return 0, nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, nil, fmt.Errorf(string(body))
-	}
+	return c.GetBlocksWithContext(context.Background(), offset, limit)
+}
+
+func (c *l2Client) GetBlocksWithContext(ctx context.Context, offset, limit int64) (uint32, []*types.Block, error) {
 	res := &types.Blocks{}
-	if err := json.Unmarshal(body, res); err != nil {
+	if err := c.get(ctx, "GetBlocks", "/api/v1/blocks", pageParams(offset, limit), res); err != nil {
 		return 0, nil, err
 	}
 	return res.Total, res.Blocks, nil
 }
 
 func (c *l2Client) GetGasAccount() (*types.GasAccount, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/gasAccount"))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
+	return c.GetGasAccountWithContext(context.Background())
+}
+
+func (c *l2Client) GetGasAccountWithContext(ctx context.Context) (*types.GasAccount, error) {
 	res := &types.GasAccount{}
-	if err := json.Unmarshal(body, res); err != nil {
+	if err := c.get(ctx, "GetGasAccount", "/api/v1/gasAccount", nil, res); err != nil {
 		return nil, err
 	}
 	return res, nil
 }
 
 func (c *l2Client) GetNftsByAccountIndex(accountIndex, offset, limit int64) (*types.Nfts, error) {
-	resp, err := HttpClient.Get(c.endpoint +
-		fmt.Sprintf("/api/v1/accountNfts?by=account_index&value=%d&limit=%d&offset=%d", accountIndex, limit, offset))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(string(body))
-	}
+	return c.GetNftsByAccountIndexWithContext(context.Background(), accountIndex, offset, limit)
+}
+
+func (c *l2Client) GetNftsByAccountIndexWithContext(ctx context.Context, accountIndex, offset, limit int64) (*types.Nfts, error) {
 	res := &types.Nfts{}
-	if err := json.Unmarshal(body, res); err != nil {
+	params := pageParams(offset, limit)
+	params.Set("by", "account_index")
+	params.Set("value", strconv.FormatInt(accountIndex, 10))
+	if err := c.get(ctx, "GetNftsByAccountIndex", "/api/v1/accountNfts", params, res); err != nil {
 		return nil, err
 	}
 	return res, nil
 }
 
 func (c *l2Client) SendRawTx(txType uint32, txInfo string) (string, error) {
-	resp, err := HttpClient.PostForm(c.endpoint+"/api/v1/sendTx",
-		url.Values{"tx_type": {strconv.Itoa(int(txType))}, "tx_info": {txInfo}})
+	return c.SendRawTxWithContext(context.Background(), txType, txInfo)
+}
+
+func (c *l2Client) SendRawTxWithContext(ctx context.Context, txType uint32, txInfo string) (txHash string, err error) {
+	defer func() { c.metrics.observeTxSent(int(txType), err) }()
+
+	if ctx.Value(stickyAccountKey{}) == nil {
+		if tx, err := txutils.ParseTxInfo(txType, txInfo); err == nil {
+			ctx = withStickyAccount(ctx, tx.GetFromAccountIndex())
+		}
+	}
+
+	// the tx is then queried from the endpoint which accepted it, the other endpoints may lag behind
+	var accepted *endpointState
+	ctx = withAcceptedEndpoint(ctx, &accepted)
+
+	res := &types.TxHash{}
+	err = c.retryPolicy.do(ctx, func(attempt int) error {
+		if attempt > 0 {
+			// The previous submission may have landed even though it failed on our side,
+			// only resubmit when the tx is surely unknown to ZkBNB.
+			sentHash, err := c.findSentTx(ctx, txType, txInfo)
+			if err != nil {
+				return &permanentError{fmt.Errorf("check the previous submission: %w", err)}
+			}
+			if sentHash != "" {
+				res.TxHash = sentHash
+				return nil
+			}
+		}
+		return c.postForm(ctx, "SendRawTx", "/api/v1/sendTx",
+			url.Values{"tx_type": {strconv.Itoa(int(txType))}, "tx_info": {txInfo}}, res)
+	})
 	if err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
+	if accepted != nil {
+		c.endpoints.stick(stickyTx(res.TxHash), accepted)
+	}
+	return res.TxHash, nil
+}
+
+// findSentTx returns the hash of the tx if it is known by ZkBNB, or an empty hash if it is not.
+func (c *l2Client) findSentTx(ctx context.Context, txType uint32, txInfo string) (string, error) {
+	txHash, err := txutils.TxHash(txType, txInfo)
 	if err != nil {
 		return "", err
 	}
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf(string(body))
+	tx := &types.EnrichedTx{}
+	err = c.get(ctx, "GetTx", "/api/v1/tx", url.Values{"hash": {txHash}}, tx)
+	if errors.Is(err, ErrTxNotFound) {
+		return "", nil
 	}
-	res := &types.TxHash{}
-	if err := json.Unmarshal(body, res); err != nil {
+	if err != nil {
 		return "", err
 	}
-	return res.TxHash, nil
+	return txHash, nil
 }
 
 func (c *l2Client) MintNft(tx *types.MintNftTxReq, ops *types.TransactOpts) (string, error) {
+	return c.MintNftWithContext(context.Background(), tx, ops)
+}
+
+func (c *l2Client) MintNftWithContext(ctx context.Context, tx *types.MintNftTxReq, ops *types.TransactOpts) (txHash string, err error) {
+	ctx, end := startSpan(ctx, c.tracer, "zkbnb.MintNft")
+	defer func() { end(err) }()
+
 	if c.keyManager == nil {
 		return "", fmt.Errorf("key manager is nil")
 	}
@@ -719,26 +730,34 @@ func (c *l2Client) MintNft(tx *types.MintNftTxReq, ops *types.TransactOpts) (str
 		ops = new(types.TransactOpts)
 	}
 
-	ops.TxType = types.TxTypeMintNft
-	ops, err := c.fullFillDefaultOps(ops)
+	tx, err = txutils.ResolveNftContent(tx)
 	if err != nil {
 		return "", err
 	}
 
-	ops, err = c.fullFillToAddrOps(ops, tx.To)
+	ops.TxType = types.TxTypeMintNft
+	ops, err = c.fullFillToAddrOps(ctx, ops, tx.To)
 	if err != nil {
 		return "", err
 	}
-
-	txInfo, err := txutils.ConstructMintNftTx(c.keyManager, tx, ops)
+	ops, err = c.fullFillDefaultOps(ctx, ops)
 	if err != nil {
 		return "", err
 	}
 
-	return c.SendRawTx(types.TxTypeMintNft, txInfo)
+	return c.signAndSend(ctx, types.TxTypeMintNft, ops, func(ops *types.TransactOpts) (string, error) {
+		return txutils.ConstructMintNftTx(c.keyManager, tx, ops)
+	})
 }
 
 func (c *l2Client) CreateCollection(tx *types.CreateCollectionReq, ops *types.TransactOpts) (string, error) {
+	return c.CreateCollectionWithContext(context.Background(), tx, ops)
+}
+
+func (c *l2Client) CreateCollectionWithContext(ctx context.Context, tx *types.CreateCollectionReq, ops *types.TransactOpts) (txHash string, err error) {
+	ctx, end := startSpan(ctx, c.tracer, "zkbnb.CreateCollection")
+	defer func() { end(err) }()
+
 	if c.keyManager == nil {
 		return "", fmt.Errorf("key manager is nil")
 	}
@@ -748,20 +767,24 @@ func (c *l2Client) CreateCollection(tx *types.CreateCollectionReq, ops *types.Tr
 	}
 
 	ops.TxType = types.TxTypeCreateCollection
-	ops, err := c.fullFillDefaultOps(ops)
-	if err != nil {
-		return "", err
-	}
-
-	txInfo, err := txutils.ConstructCreateCollectionTx(c.keyManager, tx, ops)
+	ops, err = c.fullFillDefaultOps(ctx, ops)
 	if err != nil {
 		return "", err
 	}
 
-	return c.SendRawTx(types.TxTypeCreateCollection, txInfo)
+	return c.signAndSend(ctx, types.TxTypeCreateCollection, ops, func(ops *types.TransactOpts) (string, error) {
+		return txutils.ConstructCreateCollectionTx(c.keyManager, tx, ops)
+	})
 }
 
 func (c *l2Client) CancelOffer(tx *types.CancelOfferReq, ops *types.TransactOpts) (string, error) {
+	return c.CancelOfferWithContext(context.Background(), tx, ops)
+}
+
+func (c *l2Client) CancelOfferWithContext(ctx context.Context, tx *types.CancelOfferReq, ops *types.TransactOpts) (txHash string, err error) {
+	ctx, end := startSpan(ctx, c.tracer, "zkbnb.CancelOffer")
+	defer func() { end(err) }()
+
 	if c.keyManager == nil {
 		return "", fmt.Errorf("key manager is nil")
 	}
@@ -771,19 +794,23 @@ func (c *l2Client) CancelOffer(tx *types.CancelOfferReq, ops *types.TransactOpts
 	}
 
 	ops.TxType = types.TxTypeCancelOffer
-	ops, err := c.fullFillDefaultOps(ops)
+	ops, err = c.fullFillDefaultOps(ctx, ops)
 	if err != nil {
 		return "", err
 	}
-	txInfo, err := txutils.ConstructCancelOfferTx(c.keyManager, tx, ops)
-	if err != nil {
-		return "", err
-	}
-
-	return c.SendRawTx(types.TxTypeCancelOffer, txInfo)
+	return c.signAndSend(ctx, types.TxTypeCancelOffer, ops, func(ops *types.TransactOpts) (string, error) {
+		return txutils.ConstructCancelOfferTx(c.keyManager, tx, ops)
+	})
 }
 
 func (c *l2Client) AtomicMatch(tx *types.AtomicMatchTxReq, ops *types.TransactOpts) (string, error) {
+	return c.AtomicMatchWithContext(context.Background(), tx, ops)
+}
+
+func (c *l2Client) AtomicMatchWithContext(ctx context.Context, tx *types.AtomicMatchTxReq, ops *types.TransactOpts) (txHash string, err error) {
+	ctx, end := startSpan(ctx, c.tracer, "zkbnb.AtomicMatch")
+	defer func() { end(err) }()
+
 	if c.keyManager == nil {
 		return "", fmt.Errorf("key manager is nil")
 	}
@@ -793,18 +820,23 @@ func (c *l2Client) AtomicMatch(tx *types.AtomicMatchTxReq, ops *types.TransactOp
 	}
 
 	ops.TxType = types.TxTypeAtomicMatch
-	ops, err := c.fullFillDefaultOps(ops)
+	ops, err = c.fullFillDefaultOps(ctx, ops)
 	if err != nil {
 		return "", err
 	}
-	txInfo, err := txutils.ConstructAtomicMatchTx(c.keyManager, tx, ops)
-	if err != nil {
-		return "", err
-	}
-	return c.SendRawTx(types.TxTypeAtomicMatch, txInfo)
+	return c.signAndSend(ctx, types.TxTypeAtomicMatch, ops, func(ops *types.TransactOpts) (string, error) {
+		return txutils.ConstructAtomicMatchTx(c.keyManager, tx, ops)
+	})
 }
 
 func (c *l2Client) WithdrawNft(tx *types.WithdrawNftTxReq, ops *types.TransactOpts) (string, error) {
+	return c.WithdrawNftWithContext(context.Background(), tx, ops)
+}
+
+func (c *l2Client) WithdrawNftWithContext(ctx context.Context, tx *types.WithdrawNftTxReq, ops *types.TransactOpts) (txHash string, err error) {
+	ctx, end := startSpan(ctx, c.tracer, "zkbnb.WithdrawNft")
+	defer func() { end(err) }()
+
 	if c.keyManager == nil {
 		return "", fmt.Errorf("key manager is nil")
 	}
@@ -814,20 +846,24 @@ func (c *l2Client) WithdrawNft(tx *types.WithdrawNftTxReq, ops *types.TransactOp
 	}
 
 	ops.TxType = types.TxTypeWithdrawNft
-	ops, err := c.fullFillDefaultOps(ops)
-	if err != nil {
-		return "", err
-	}
-
-	txInfo, err := txutils.ConstructWithdrawNftTx(c.keyManager, tx, ops)
+	ops, err = c.fullFillDefaultOps(ctx, ops)
 	if err != nil {
 		return "", err
 	}
 
-	return c.SendRawTx(types.TxTypeWithdrawNft, txInfo)
+	return c.signAndSend(ctx, types.TxTypeWithdrawNft, ops, func(ops *types.TransactOpts) (string, error) {
+		return txutils.ConstructWithdrawNftTx(c.keyManager, tx, ops)
+	})
 }
 
 func (c *l2Client) TransferNft(tx *types.TransferNftTxReq, ops *types.TransactOpts) (string, error) {
+	return c.TransferNftWithContext(context.Background(), tx, ops)
+}
+
+func (c *l2Client) TransferNftWithContext(ctx context.Context, tx *types.TransferNftTxReq, ops *types.TransactOpts) (txHash string, err error) {
+	ctx, end := startSpan(ctx, c.tracer, "zkbnb.TransferNft")
+	defer func() { end(err) }()
+
 	if c.keyManager == nil {
 		return "", fmt.Errorf("key manager is nil")
 	}
@@ -837,24 +873,28 @@ func (c *l2Client) TransferNft(tx *types.TransferNftTxReq, ops *types.TransactOp
 	}
 
 	ops.TxType = types.TxTypeTransferNft
-	ops, err := c.fullFillDefaultOps(ops)
+	ops, err = c.fullFillToAddrOps(ctx, ops, tx.To)
 	if err != nil {
 		return "", err
 	}
-	ops, err = c.fullFillToAddrOps(ops, tx.To)
+	ops, err = c.fullFillDefaultOps(ctx, ops)
 	if err != nil {
 		return "", err
 	}
 
-	txInfo, err := txutils.ConstructTransferNftTx(c.keyManager, tx, ops)
-	if err != nil {
-		return "", err
-	}
-
-	return c.SendRawTx(types.TxTypeTransferNft, txInfo)
+	return c.signAndSend(ctx, types.TxTypeTransferNft, ops, func(ops *types.TransactOpts) (string, error) {
+		return txutils.ConstructTransferNftTx(c.keyManager, tx, ops)
+	})
 }
 
 func (c *l2Client) Withdraw(tx *types.WithdrawReq, ops *types.TransactOpts) (string, error) {
+	return c.WithdrawWithContext(context.Background(), tx, ops)
+}
+
+func (c *l2Client) WithdrawWithContext(ctx context.Context, tx *types.WithdrawReq, ops *types.TransactOpts) (txHash string, err error) {
+	ctx, end := startSpan(ctx, c.tracer, "zkbnb.Withdraw")
+	defer func() { end(err) }()
+
 	if c.keyManager == nil {
 		return "", fmt.Errorf("key manager is nil")
 	}
@@ -863,21 +903,29 @@ func (c *l2Client) Withdraw(tx *types.WithdrawReq, ops *types.TransactOpts) (str
 		ops = new(types.TransactOpts)
 	}
 
-	ops.TxType = types.TxTypeWithdraw
-	ops, err := c.fullFillDefaultOps(ops)
-	if err != nil {
+	if err := checkTxAmount(tx); err != nil {
 		return "", err
 	}
 
-	txInfo, err := txutils.ConstructWithdrawTxInfo(c.keyManager, tx, ops)
+	ops.TxType = types.TxTypeWithdraw
+	ops, err = c.fullFillDefaultOps(ctx, ops)
 	if err != nil {
 		return "", err
 	}
 
-	return c.SendRawTx(types.TxTypeWithdraw, txInfo)
+	return c.signAndSend(ctx, types.TxTypeWithdraw, ops, func(ops *types.TransactOpts) (string, error) {
+		return txutils.ConstructWithdrawTxInfo(c.keyManager, tx, ops)
+	})
 }
 
 func (c *l2Client) Transfer(tx *types.TransferTxReq, ops *types.TransactOpts) (string, error) {
+	return c.TransferWithContext(context.Background(), tx, ops)
+}
+
+func (c *l2Client) TransferWithContext(ctx context.Context, tx *types.TransferTxReq, ops *types.TransactOpts) (txHash string, err error) {
+	ctx, end := startSpan(ctx, c.tracer, "zkbnb.Transfer")
+	defer func() { end(err) }()
+
 	if c.keyManager == nil {
 		return "", fmt.Errorf("key manager is nil")
 	}
@@ -886,24 +934,77 @@ func (c *l2Client) Transfer(tx *types.TransferTxReq, ops *types.TransactOpts) (s
 		ops = new(types.TransactOpts)
 	}
 
+	if err := checkTxAmount(tx); err != nil {
+		return "", err
+	}
+
 	ops.TxType = types.TxTypeTransfer
-	ops, err := c.fullFillDefaultOps(ops)
+	ops, err = c.fullFillToAddrOps(ctx, ops, tx.ToAccountName)
 	if err != nil {
 		return "", err
 	}
-	ops, err = c.fullFillToAddrOps(ops, tx.ToAccountName)
+	ops, err = c.fullFillDefaultOps(ctx, ops)
 	if err != nil {
 		return "", err
 	}
-	txInfo, err := txutils.ConstructTransferTx(c.keyManager, ops, tx)
+	return c.signAndSend(ctx, types.TxTypeTransfer, ops, func(ops *types.TransactOpts) (string, error) {
+		return txutils.ConstructTransferTx(c.keyManager, ops, tx)
+	})
+}
+
+// checkTxAmount fails fast when the amount of a transfer is not packable, or when the amount of a withdrawal is out
+// of range. The amounts of the withdrawals are signed as is, they are not packed.
+func checkTxAmount(req interface{}) error {
+	switch req := req.(type) {
+	case *types.TransferTxReq:
+		if req.Amount != nil {
+			if err := req.Amount.Validate(); err != nil {
+				return err
+			}
+		}
+		_, amount := req.AssetAndAmount()
+		return txutils.CheckPackableAmount(amount)
+	case *types.WithdrawReq:
+		if req.Amount != nil {
+			if err := req.Amount.Validate(); err != nil {
+				return err
+			}
+		}
+		_, amount := req.AssetAndAmount()
+		return txutils.CheckAmountRange(amount)
+	}
+	return nil
+}
+
+// checkGasFee fails fast when the gas fee of the ops is not packable, or rounds it up with WithGasFeeRoundUp
+func (c *l2Client) checkGasFee(ops *types.TransactOpts) error {
+	if ops.GasFee != nil {
+		if err := ops.GasFee.Validate(); err != nil {
+			return err
+		}
+	}
+	_, fee := ops.GasFeeAssetAndAmount()
+	if !c.roundUpGasFee {
+		return txutils.CheckPackableFee(fee)
+	}
+	rounded, err := txutils.RoundUpFee(fee)
 	if err != nil {
-		return "", err
+		return err
+	}
+	if ops.GasFee != nil {
+		ops.GasFee = &types.Amount{Asset: ops.GasFee.Asset, Value: rounded}
+	} else {
+		ops.GasFeeAssetAmount = rounded
 	}
-	return c.SendRawTx(types.TxTypeTransfer, txInfo)
+	return nil
 }
 
-func (c *l2Client) fullFillToAddrOps(ops *types.TransactOpts, to string) (*types.TransactOpts, error) {
-	toAccount, err := c.GetAccountByName(to)
+func (c *l2Client) fullFillToAddrOps(ctx context.Context, ops *types.TransactOpts, to string) (*types.TransactOpts, error) {
+	to, err := zns.Normalize(to)
+	if err != nil {
+		return nil, err
+	}
+	toAccount, err := c.GetAccountByNameWithContext(ctx, to)
 	if err != nil {
 		return nil, err
 	}
@@ -916,12 +1017,15 @@ func (c *l2Client) fullFillToAddrOps(ops *types.TransactOpts, to string) (*types
 	return ops, nil
 }
 
-func (c *l2Client) fullFillDefaultOps(ops *types.TransactOpts) (*types.TransactOpts, error) {
+func (c *l2Client) fullFillDefaultOps(ctx context.Context, ops *types.TransactOpts) (_ *types.TransactOpts, err error) {
+	ctx, end := startSpan(ctx, c.tracer, "zkbnb.fullFillDefaultOps")
+	defer func() { end(err) }()
+
 	if ops == nil {
 		ops = new(types.TransactOpts)
 	}
 	if ops.GasAccountIndex == 0 {
-		gasAccount, err := c.GetGasAccount()
+		gasAccount, err := c.GetGasAccountWithContext(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -934,29 +1038,39 @@ func (c *l2Client) fullFillDefaultOps(ops *types.TransactOpts) (*types.TransactO
 		ops.ExpiredAt = time.Now().Add(defaultExpireTime).UnixMilli()
 	}
 	if ops.FromAccountIndex == 0 {
-		l2Account, err := c.GetAccountByPk(hex.EncodeToString(c.keyManager.PubKey().Bytes()))
+		l2Account, err := c.GetAccountByPkWithContext(ctx, hex.EncodeToString(c.keyManager.PubKey().Bytes()))
 		if err != nil {
 			return nil, err
 		}
 		ops.FromAccountIndex = l2Account.Index
 	}
-	if ops.Nonce == 0 {
-		nonce, err := c.GetNextNonce(ops.FromAccountIndex)
-		if err != nil {
-			return nil, err
-		}
-		ops.Nonce = nonce
-	}
 	if len(ops.CallDataHash) == 0 {
 		hFunc := mimc.NewMiMC()
 		ops.CallDataHash = hFunc.Sum([]byte(ops.CallData))
 	}
-	if ops.GasFeeAssetAmount == nil {
-		gas, err := c.GetGasFee(ops.GasFeeAssetId, ops.TxType)
+	if ops.GasFee == nil && ops.GasFeeAssetAmount == nil {
+		gas, err := c.GetGasFeeWithContext(ctx, ops.GasFeeAssetId, ops.TxType)
 		if err != nil {
 			return nil, err
 		}
 		ops.GasFeeAssetAmount = gas
 	}
+	if err := c.checkGasFee(ops); err != nil {
+		return nil, err
+	}
+	// the nonce is the last, so that a nonce handed out by the nonce manager is not lost by a failed lookup
+	if ops.Nonce == 0 && c.nonces != nil {
+		nonce, err := c.nonces.Next(ctx, ops.FromAccountIndex)
+		if err != nil {
+			return nil, err
+		}
+		ops.Nonce = nonce
+	} else if ops.Nonce == 0 {
+		nonce, err := c.GetNextNonceWithContext(withStickyAccount(ctx, ops.FromAccountIndex), ops.FromAccountIndex)
+		if err != nil {
+			return nil, err
+		}
+		ops.Nonce = nonce
+	}
 	return ops, nil
 }
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryWithCanceledContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"height":1}`))
+	}))
+	defer server.Close()
+
+	c := &l2Client{endpoint: server.URL}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.GetCurrentHeightWithContext(ctx)
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	height, err := c.GetCurrentHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), height)
+}
+
+func TestQueryWithContextDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	c := &l2Client{endpoint: server.URL}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := c.GetGasAccountWithContext(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
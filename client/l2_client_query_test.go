@@ -0,0 +1,234 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const trickyInput = "a&b=c#d +e/f?g%h"
+
+type recordingServer struct {
+	*httptest.Server
+	mu    sync.Mutex
+	path  string
+	query url.Values
+}
+
+func newRecordingServer() *recordingServer {
+	s := &recordingServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.path = r.URL.Path
+		s.query = r.URL.Query()
+		s.mu.Unlock()
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	return s
+}
+
+func (s *recordingServer) last() (string, url.Values) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.path, s.query
+}
+
+func TestQueryParamsEncoding(t *testing.T) {
+	server := newRecordingServer()
+	defer server.Close()
+	c := &l2Client{endpoint: server.URL}
+
+	tests := []struct {
+		name  string
+		call  func() error
+		path  string
+		query url.Values
+	}{
+		{
+			name: "GetAccountByName",
+			call: func() error { _, err := c.GetAccountByName(trickyInput); return err },
+			path: "/api/v1/account", query: url.Values{"by": {"name"}, "value": {trickyInput}},
+		},
+		{
+			name: "GetAccountByPk",
+			call: func() error { _, err := c.GetAccountByPk(trickyInput); return err },
+			path: "/api/v1/account", query: url.Values{"by": {"pk"}, "value": {trickyInput}},
+		},
+		{
+			name: "GetAccountByIndex",
+			call: func() error { _, err := c.GetAccountByIndex(5); return err },
+			path: "/api/v1/account", query: url.Values{"by": {"index"}, "value": {"5"}},
+		},
+		{
+			name: "GetAccounts",
+			call: func() error { _, err := c.GetAccounts(10, 20); return err },
+			path: "/api/v1/accounts", query: url.Values{"offset": {"10"}, "limit": {"20"}},
+		},
+		{
+			name: "Search",
+			call: func() error { _, err := c.Search(trickyInput); return err },
+			path: "/api/v1/search", query: url.Values{"keyword": {trickyInput}},
+		},
+		{
+			name: "GetAssetBySymbol",
+			call: func() error { _, err := c.GetAssetBySymbol(trickyInput); return err },
+			path: "/api/v1/asset", query: url.Values{"by": {"symbol"}, "value": {trickyInput}},
+		},
+		{
+			name: "GetAssetById",
+			call: func() error { _, err := c.GetAssetById(3); return err },
+			path: "/api/v1/asset", query: url.Values{"by": {"id"}, "value": {"3"}},
+		},
+		{
+			name: "GetAssets",
+			call: func() error { _, err := c.GetAssets(1, 2); return err },
+			path: "/api/v1/assets", query: url.Values{"offset": {"1"}, "limit": {"2"}},
+		},
+		{
+			name: "GetGasFee",
+			call: func() error { _, err := c.GetGasFee(1, 4); return err },
+			path: "/api/v1/gasFee", query: url.Values{"asset_id": {"1"}, "tx_type": {"4"}},
+		},
+		{
+			name: "GetGasFeeAssets",
+			call: func() error { _, err := c.GetGasFeeAssets(); return err },
+			path: "/api/v1/gasFeeAssets", query: url.Values{},
+		},
+		{
+			name: "GetGasAccount",
+			call: func() error { _, err := c.GetGasAccount(); return err },
+			path: "/api/v1/gasAccount", query: url.Values{},
+		},
+		{
+			name: "GetLayer2BasicInfo",
+			call: func() error { _, err := c.GetLayer2BasicInfo(); return err },
+			path: "/api/v1/layer2BasicInfo", query: url.Values{},
+		},
+		{
+			name: "GetCurrentHeight",
+			call: func() error { _, err := c.GetCurrentHeight(); return err },
+			path: "/api/v1/currentHeight", query: url.Values{},
+		},
+		{
+			name: "GetBlockByCommitment",
+			call: func() error { _, err := c.GetBlockByCommitment(trickyInput); return err },
+			path: "/api/v1/block", query: url.Values{"by": {"commitment"}, "value": {trickyInput}},
+		},
+		{
+			name: "GetBlockByHeight",
+			call: func() error { _, err := c.GetBlockByHeight(9); return err },
+			path: "/api/v1/block", query: url.Values{"by": {"height"}, "value": {"9"}},
+		},
+		{
+			name: "GetBlocks",
+			call: func() error { _, _, err := c.GetBlocks(0, 10); return err },
+			path: "/api/v1/blocks", query: url.Values{"offset": {"0"}, "limit": {"10"}},
+		},
+		{
+			name: "GetTx",
+			call: func() error { _, err := c.GetTx(trickyInput); return err },
+			path: "/api/v1/tx", query: url.Values{"hash": {trickyInput}},
+		},
+		{
+			name: "GetTxs",
+			call: func() error { _, _, err := c.GetTxs(4, 5); return err },
+			path: "/api/v1/txs", query: url.Values{"offset": {"4"}, "limit": {"5"}},
+		},
+		{
+			name: "GetTxsByAccountPk",
+			call: func() error {
+				_, _, err := c.GetTxsByAccountPk(trickyInput, 0, 10, GetTxWithTypes([]int64{4, 5}))
+				return err
+			},
+			path: "/api/v1/accountTxs",
+			query: url.Values{"by": {"account_pk"}, "value": {trickyInput}, "offset": {"0"}, "limit": {"10"},
+				"types": {"[4,5]"}},
+		},
+		{
+			name:  "GetTxsByAccountName",
+			call:  func() error { _, _, err := c.GetTxsByAccountName(trickyInput, 0, 10); return err },
+			path:  "/api/v1/accountTxs",
+			query: url.Values{"by": {"account_name"}, "value": {trickyInput}, "offset": {"0"}, "limit": {"10"}},
+		},
+		{
+			name: "GetTxsByAccountIndex",
+			call: func() error {
+				_, _, err := c.GetTxsByAccountIndex(2, 0, 10, GetTxWithTypes([]int64{7}))
+				return err
+			},
+			path:  "/api/v1/accountTxs",
+			query: url.Values{"by": {"account_index"}, "value": {"2"}, "offset": {"0"}, "limit": {"10"}, "types": {"[7]"}},
+		},
+		{
+			name: "GetTxsByBlockHeight",
+			call: func() error { _, err := c.GetTxsByBlockHeight(8); return err },
+			path: "/api/v1/blockTxs", query: url.Values{"by": {"block_height"}, "value": {"8"}},
+		},
+		{
+			name: "GetPendingTxs",
+			call: func() error { _, _, err := c.GetPendingTxs(0, 3); return err },
+			path: "/api/v1/pendingTxs", query: url.Values{"offset": {"0"}, "limit": {"3"}},
+		},
+		{
+			name: "GetPendingTxsByAccountName",
+			call: func() error {
+				_, _, err := c.GetPendingTxsByAccountName(trickyInput, GetTxWithTypes([]int64{4}))
+				return err
+			},
+			path:  "/api/v1/accountPendingTxs",
+			query: url.Values{"by": {"account_name"}, "value": {trickyInput}, "types": {"[4]"}},
+		},
+		{
+			name: "GetExecutedTxs",
+			call: func() error {
+				_, _, err := c.GetExecutedTxs(0, 3, GetTxWithFromHash(trickyInput))
+				return err
+			},
+			path:  "/api/v1/executedTxs",
+			query: url.Values{"offset": {"0"}, "limit": {"3"}, "from_hash": {trickyInput}},
+		},
+		{
+			name: "GetNextNonce",
+			call: func() error { _, err := c.GetNextNonce(6); return err },
+			path: "/api/v1/nextNonce", query: url.Values{"account_index": {"6"}},
+		},
+		{
+			name: "GetMaxOfferId",
+			call: func() error { _, err := c.GetMaxOfferId(6); return err },
+			path: "/api/v1/maxOfferId", query: url.Values{"account_index": {"6"}},
+		},
+		{
+			name: "GetNftsByAccountIndex",
+			call: func() error { _, err := c.GetNftsByAccountIndex(6, 0, 20); return err },
+			path: "/api/v1/accountNfts", query: url.Values{"by": {"account_index"}, "value": {"6"}, "offset": {"0"}, "limit": {"20"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.NoError(t, test.call())
+			path, query := server.last()
+			assert.Equal(t, test.path, path)
+			assert.Equal(t, test.query, query)
+		})
+	}
+}
+
+func TestSendRawTxFormEncoding(t *testing.T) {
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		form = r.PostForm
+		_, _ = w.Write([]byte(`{"tx_hash":"hash"}`))
+	}))
+	defer server.Close()
+
+	c := &l2Client{endpoint: server.URL}
+	_, err := c.SendRawTx(4, `{"Memo":"`+trickyInput+`"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{"tx_type": {"4"}, "tx_info": {`{"Memo":"` + trickyInput + `"}`}}, form)
+}
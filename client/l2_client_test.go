@@ -1,193 +1,250 @@
 package client
 
 import (
+	"context"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"math/big"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/bnb-chain/zkbnb-go-sdk/accounts"
+	"github.com/bnb-chain/zkbnb-go-sdk/amount"
 	"github.com/bnb-chain/zkbnb-go-sdk/txutils"
 	"github.com/bnb-chain/zkbnb-go-sdk/types"
+	"github.com/bnb-chain/zkbnb-go-sdk/zkbnbtest"
+	"github.com/bnb-chain/zkbnb-go-sdk/zns"
 )
 
-var testEndpoint = "http://127.0.0.1:8888"
 var seed = "28e1a3762ff9944e9a4ad79477b756ef0aff3d2af76f0f40a0c3ec6ca76cf24b"
-
-func getSdkClient() *l2Client {
-	c := &l2Client{
-		endpoint: testEndpoint,
-	}
-	keyManager, _ := accounts.NewSeedKeyManager(seed)
+var buyerSeed = "17673b9a9fdec6dc90c7cc1eb1c939134dfb659d2f08edbe071e5c45f343d008"
+
+// getSdkClient returns a client of sher.legend (index 2) talking to a fake server, gavin.legend (index 3) is
+// another account of the server.
+func getSdkClient(t *testing.T) (*l2Client, *zkbnbtest.Server) {
+	server := zkbnbtest.NewServer()
+	t.Cleanup(server.Close)
+
+	keyManager, err := server.AddAccountFromSeed(2, "sher.legend", seed)
+	require.NoError(t, err)
+	_, err = server.AddAccountFromSeed(3, "gavin.legend", buyerSeed)
+	require.NoError(t, err)
+	server.AddAsset(&types.Asset{Id: 1, Name: "LEG", Decimals: 18, Symbol: "LEG", Price: "1.00"})
+	server.SetBalance(2, 0, big.NewInt(1e18))
+	server.SetBalance(2, 1, big.NewInt(1e18))
+	server.SetBalance(3, 0, big.NewInt(1e18))
+	server.AddCollection(2)
+	for _, index := range []int64{0, 3, 17} {
+		server.AddNft(&types.Nft{
+			Index:               index,
+			OwnerAccountIndex:   2,
+			OwnerAccountName:    "sher.legend",
+			CreatorAccountIndex: 2,
+			CreatorAccountName:  "sher.legend",
+			ContentHash:         txutils.NftContentHash(fmt.Sprintf("content_hash%d", index)),
+		})
+	}
+
+	c := NewZkBNBClient(server.URL).(*l2Client)
 	c.SetKeyManager(keyManager)
-	return c
+	return c, server
+}
+
+// lastAcceptedTx returns the last tx accepted by the server
+func lastAcceptedTx(t *testing.T, server *zkbnbtest.Server) *zkbnbtest.SentTx {
+	accepted := server.AcceptedTxs()
+	require.NotEmpty(t, accepted)
+	return accepted[len(accepted)-1]
 }
 
 func TestGetCurrentHeight(t *testing.T) {
-	sdkClient := getSdkClient()
-	height, err := sdkClient.GetCurrentHeight()
-	if err != nil {
-		println(err.Error())
-		return
-	}
+	sdkClient, server := getSdkClient(t)
+	server.AddBlock(&types.Block{Height: 1})
+	server.AddBlock(&types.Block{Height: 2})
 
-	println("current height: ", height)
+	height, err := sdkClient.GetCurrentHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), height)
 }
 
 func TestGetAsset(t *testing.T) {
-	sdkClient := getSdkClient()
+	sdkClient, _ := getSdkClient(t)
 	asset, err := sdkClient.GetAssetBySymbol("BNB")
-	if err != nil {
-		println(err.Error())
-		return
-	}
+	assert.NoError(t, err)
+	assert.Equal(t, "300.00", asset.Price)
 
-	println("bnb price: ", asset.Price)
+	_, err = sdkClient.GetAssetBySymbol("UNKNOWN")
+	assert.ErrorIs(t, err, ErrAssetNotFound)
 }
 
 func TestGetAccountNfts(t *testing.T) {
-	sdkClient := getSdkClient()
-	nfts, err := sdkClient.GetNftsByAccountIndex(5, 0, 100)
-	if err != nil {
-		println(err.Error())
-		return
+	sdkClient, server := getSdkClient(t)
+	// a nft created by sher.legend and owned by gavin.legend
+	server.AddNft(&types.Nft{
+		Index:               5,
+		OwnerAccountIndex:   3,
+		OwnerAccountName:    "gavin.legend",
+		CreatorAccountIndex: 2,
+		CreatorAccountName:  "sher.legend",
+		ContentHash:         txutils.NftContentHash("content_hash5"),
+	})
+
+	nfts, err := sdkClient.GetNftsByAccountIndex(3, 0, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), nfts.Total)
+	if assert.Len(t, nfts.Nfts, 1) {
+		assert.Equal(t, int64(5), nfts.Nfts[0].Index)
+		assert.Equal(t, "sher.legend", nfts.Nfts[0].CreatorAccountName)
+		assert.Equal(t, "gavin.legend", nfts.Nfts[0].OwnerAccountName)
 	}
 
-	println("nft total: ", nfts.Total)
-	if len(nfts.Nfts) > 0 {
-		println("creator: ", nfts.Nfts[0].CreatorAccountName)
-		println("owner: ", nfts.Nfts[0].OwnerAccountName)
-	}
+	_, err = sdkClient.GetNftsByAccountIndex(5, 0, 100)
+	assert.ErrorIs(t, err, ErrAccountNotFound)
 }
 
 func TestGetGasAccount(t *testing.T) {
-	sdkClient := getSdkClient()
+	sdkClient, _ := getSdkClient(t)
 	account, err := sdkClient.GetGasAccount()
-	if err != nil {
-		println(err.Error())
-		return
-	}
-
-	println("gas account index: ", account.Index)
+	assert.NoError(t, err)
+	assert.Equal(t, zkbnbtest.DefaultGasAccount.Index, account.Index)
 }
 
 func TestGetNftsByAccountIndex(t *testing.T) {
-	sdkClient := getSdkClient()
-	account, err := sdkClient.GetNftsByAccountIndex(2, 0, 10)
-	if err != nil {
-		println(err.Error())
-		return
+	sdkClient, _ := getSdkClient(t)
+	nfts, err := sdkClient.GetNftsByAccountIndex(2, 0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), nfts.Total)
+	if assert.Len(t, nfts.Nfts, 2) {
+		assert.Equal(t, int64(0), nfts.Nfts[0].Index)
+		assert.Equal(t, "sher.legend", nfts.Nfts[0].OwnerAccountName)
 	}
 
-	println("account total nft count: ", account.Total)
-	bz, _ := json.MarshalIndent(account.Nfts, "", "  ")
-	println(string(bz))
+	nfts, err = sdkClient.GetNftsByAccountIndex(3, 0, 10)
+	assert.NoError(t, err)
+	assert.Zero(t, nfts.Total)
 }
 
 func TestGetAssets(t *testing.T) {
-	sdkClient := getSdkClient()
+	sdkClient, _ := getSdkClient(t)
 	assetList, err := sdkClient.GetAssets(0, 50)
-	if err != nil {
-		println(err.Error())
-		return
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), assetList.Total)
+	if assert.Len(t, assetList.Assets, 2) {
+		assert.Equal(t, "BNB", assetList.Assets[0].Symbol)
+		assert.Equal(t, "LEG", assetList.Assets[1].Symbol)
 	}
-
-	bz, _ := json.MarshalIndent(assetList, "", "  ")
-	println(string(bz))
 }
 
 func TestGetTxs(t *testing.T) {
-	sdkClient := getSdkClient()
+	sdkClient, server := getSdkClient(t)
+	server.AddBlock(&types.Block{Height: 1, Txs: []*types.Tx{
+		{Hash: "tx1", Type: types.TxTypeTransfer, Status: int64(types.TxStatusVerified)},
+		{Hash: "tx2", Type: types.TxTypeWithdraw, Status: int64(types.TxStatusVerified)},
+	}})
+
 	total, txList, err := sdkClient.GetTxs(0, 10)
-	if err != nil {
-		println(err.Error())
-		return
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), total)
+	if assert.Len(t, txList, 2) {
+		// the newest first
+		assert.Equal(t, "tx2", txList[0].Hash)
+		assert.Equal(t, int64(1), txList[0].BlockHeight)
 	}
-
-	bz, _ := json.MarshalIndent(txList, "", "  ")
-	println(total)
-	println(string(bz))
 }
 
 func TestCreateCollection(t *testing.T) {
-	sdkClient := getSdkClient()
+	sdkClient, server := getSdkClient(t)
 	txInfo := &types.CreateCollectionReq{
-		Name:         fmt.Sprintf("Nft Collection - my collection"),
+		Name:         "Nft Collection - my collection",
 		Introduction: "Great Nft!",
 	}
 
 	txHash, err := sdkClient.CreateCollection(txInfo, nil)
-	if err != nil {
-		println(err.Error())
-		return
-	}
-	fmt.Printf("create collection success, tx_hash=%s \n", txHash)
+	assert.NoError(t, err)
+
+	sent := lastAcceptedTx(t, server)
+	assert.Equal(t, txHash, sent.Hash)
+	assert.Equal(t, uint32(types.TxTypeCreateCollection), sent.TxType)
 }
 
 func TestMintNft(t *testing.T) {
-	sdkClient := getSdkClient()
+	sdkClient, server := getSdkClient(t)
 
 	contentHash := txutils.NftContentHash("contend_hash1")
 	txInfo := &types.MintNftTxReq{
-		To:                  "sher.legend",
+		To:                  "gavin.legend",
 		NftContentHash:      contentHash,
 		NftCollectionId:     0,
 		CreatorTreasuryRate: 0,
 	}
 
 	txHash, err := sdkClient.MintNft(txInfo, nil)
+	require.NoError(t, err)
+
+	tx, err := sdkClient.GetTx(txHash)
 	assert.NoError(t, err)
-	fmt.Printf("mint nft success, tx_hash=%s \n", txHash)
+	assert.Equal(t, types.TxStatusPending, tx.TxStatus())
+	assert.Equal(t, int64(3), tx.ToAccountIndex)
+	assert.Equal(t, lastAcceptedTx(t, server).Hash, txHash)
+}
 
+func TestMintNftContent(t *testing.T) {
+	sdkClient, server := getSdkClient(t)
+
+	cid := "QmY7Yh4UquoXHLPFo2XbhXkhBvFoPwmQUSa92pxnxjQuPU"
+	_, err := sdkClient.MintNft(&types.MintNftTxReq{To: "gavin.legend", NftCID: cid}, nil)
+	require.NoError(t, err)
+	tx, err := types.ParseMintNftTxInfo(lastAcceptedTx(t, server).TxInfo)
+	require.NoError(t, err)
+	contentHash, err := txutils.NftContentHashFromCID(cid)
+	require.NoError(t, err)
+	assert.Equal(t, contentHash, tx.NftContentHash)
+
+	unsigned, err := sdkClient.PrepareTx(&types.MintNftTxReq{To: "gavin.legend", NftContent: []byte("an image")}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, &types.MintNftTxReq{To: "gavin.legend", NftContentHash: txutils.NftContentHashOf([]byte("an image"))}, unsigned.Req)
+
+	_, err = sdkClient.MintNft(&types.MintNftTxReq{To: "gavin.legend"}, nil)
+	assert.ErrorIs(t, err, txutils.ErrNoNftContent)
+	_, err = sdkClient.MintNft(&types.MintNftTxReq{To: "gavin.legend", NftMetadata: `{"name":`}, nil)
+	assert.Error(t, err)
+	_, err = sdkClient.MintNft(&types.MintNftTxReq{To: "gavin.legend", NftContentHash: contentHash, NftCID: cid}, nil)
+	assert.ErrorIs(t, err, txutils.ErrConflictingNftContent)
+	assert.Len(t, server.SentTxs(), 1)
 }
 
 func TestAtomicMatchTx(t *testing.T) {
-	sellerSeed := "28e1a3762ff9944e9a4ad79477b756ef0aff3d2af76f0f40a0c3ec6ca76cf24b"
 	sellerName := "sher.legend"
-
-	buyerSeed := "17673b9a9fdec6dc90c7cc1eb1c939134dfb659d2f08edbe071e5c45f343d008"
 	buyerName := "gavin.legend"
 
-	sdkClient := getSdkClient()
+	sdkClient, server := getSdkClient(t)
+	server.SetMaxOfferId(3, 4)
 
 	buyer, err := sdkClient.GetAccountByName(buyerName)
-	if err != nil {
-		println(err.Error())
-		return
-	}
-
+	require.NoError(t, err)
 	seller, err := sdkClient.GetAccountByName(sellerName)
-	if err != nil {
-		println(err.Error())
-		return
-	}
+	require.NoError(t, err)
 
 	buyerOfferId, err := sdkClient.GetMaxOfferId(buyer.Index)
-	if err != nil {
-		println(err.Error())
-		return
-	}
-
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), buyerOfferId)
 	sellerOfferId, err := sdkClient.GetMaxOfferId(seller.Index)
-	if err != nil {
-		println(err.Error())
-		return
-	}
+	require.NoError(t, err)
 
 	nftIndex := int64(0)
-
-	txInfo := PrepareAtomicMatchInfo(sdkClient, buyerSeed, sellerSeed, nftIndex, buyer.Index, int64(buyerOfferId), seller.Index, int64(sellerOfferId))
+	txInfo := PrepareAtomicMatchInfo(sdkClient, buyerSeed, seed, nftIndex, buyer.Index, int64(buyerOfferId), seller.Index, int64(sellerOfferId))
 
 	txId, err := sdkClient.SendRawTx(types.TxTypeAtomicMatch, txInfo)
-	if err != nil {
-		println(err.Error())
-		return
-	}
-	fmt.Printf("send atomic match tx success, tx_id=%s \n", txId)
+	assert.NoError(t, err)
+	assert.Equal(t, lastAcceptedTx(t, server).Hash, txId)
+
+	// the offers must be signed by their owners
+	txInfo = PrepareAtomicMatchInfo(sdkClient, seed, seed, nftIndex, buyer.Index, int64(buyerOfferId), seller.Index, int64(sellerOfferId))
+	_, err = sdkClient.SendRawTx(types.TxTypeAtomicMatch, txInfo)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
 }
 
 func PrepareAtomicMatchInfo(c *l2Client, buyerSeed, sellerSeed string, nftIndex, buyerIndex, buyerOfferId, sellerIndex, sellerOfferId int64) string {
@@ -238,8 +295,9 @@ func PrepareAtomicMatchInfo(c *l2Client, buyerSeed, sellerSeed string, nftIndex,
 		panic(err)
 	}
 
-	signedBuyOffer, _ := types.ParseOfferTxInfo(buyTx)
-	signedSellOffer, _ := types.ParseOfferTxInfo(sellTx)
+	signedBuyOffer, signedSellOffer := &types.SignedOffer{}, &types.SignedOffer{}
+	_ = signedBuyOffer.Unmarshal(buyTx)
+	_ = signedSellOffer.Unmarshal(sellTx)
 
 	txInfo := &types.AtomicMatchTxReq{
 		BuyOffer:  signedBuyOffer,
@@ -247,12 +305,12 @@ func PrepareAtomicMatchInfo(c *l2Client, buyerSeed, sellerSeed string, nftIndex,
 	}
 
 	ops := new(types.TransactOpts)
-	ops, err = c.fullFillDefaultOps(ops)
+	ops, err = c.fullFillDefaultOps(context.Background(), ops)
 	if err != nil {
 		panic(err)
 	}
 
-	tx, err := txutils.ConstructAtomicMatchTx(sellerKey, txInfo, ops)
+	tx, err := txutils.ConstructAtomicMatchTx(c.KeyManager(), txInfo, ops)
 	if err != nil {
 		panic(err)
 	}
@@ -262,17 +320,18 @@ func PrepareAtomicMatchInfo(c *l2Client, buyerSeed, sellerSeed string, nftIndex,
 func TestTransferNft(t *testing.T) {
 	toAccountName := "gavin.legend"
 
-	sdkClient := getSdkClient()
+	sdkClient, server := getSdkClient(t)
 
 	nftIndex := int64(3)
 	txInfo := PrepareTransferNftTxInfo(sdkClient, nftIndex, toAccountName)
 
 	txId, err := sdkClient.SendRawTx(types.TxTypeTransferNft, txInfo)
-	if err != nil {
-		fmt.Println("err: ", err.Error())
-		return
-	}
-	fmt.Printf("send transfer nft tx success, tx_id=%s \n", txId)
+	assert.NoError(t, err)
+	assert.Equal(t, lastAcceptedTx(t, server).Hash, txId)
+
+	// the nonce is used already
+	_, err = sdkClient.SendRawTx(types.TxTypeTransferNft, txInfo)
+	assert.ErrorIs(t, err, ErrInvalidNonce)
 }
 
 func PrepareTransferNftTxInfo(c *l2Client, nftIndex int64, toAccountName string) string {
@@ -281,12 +340,12 @@ func PrepareTransferNftTxInfo(c *l2Client, nftIndex int64, toAccountName string)
 		To:       toAccountName,
 	}
 	ops := new(types.TransactOpts)
-	ops, err := c.fullFillDefaultOps(ops)
+	ops, err := c.fullFillDefaultOps(context.Background(), ops)
 	if err != nil {
 		panic(err)
 	}
 
-	ops, err = c.fullFillToAddrOps(ops, toAccountName)
+	ops, err = c.fullFillToAddrOps(context.Background(), ops, toAccountName)
 	if err != nil {
 		panic(err)
 	}
@@ -298,28 +357,21 @@ func PrepareTransferNftTxInfo(c *l2Client, nftIndex int64, toAccountName string)
 }
 
 func TestCancelOfferTx(t *testing.T) {
-	sdkClient := getSdkClient()
+	sdkClient, server := getSdkClient(t)
+	server.SetMaxOfferId(2, 7)
 
 	account, err := sdkClient.GetAccountByPk(hex.EncodeToString(sdkClient.KeyManager().PubKey().Bytes()))
-	if err != nil {
-		println(err.Error())
-		return
-	}
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), account.Index)
 
 	offerId, err := sdkClient.GetMaxOfferId(account.Index)
-	if err != nil {
-		println(err.Error())
-		return
-	}
+	require.NoError(t, err)
 
 	txInfo := PrepareCancelOfferTxInfo(sdkClient, int64(offerId))
 
 	txId, err := sdkClient.SendRawTx(types.TxTypeCancelOffer, txInfo)
-	if err != nil {
-		println(err.Error())
-		return
-	}
-	fmt.Printf("send cancel offer success, tx_id=%s \n", txId)
+	assert.NoError(t, err)
+	assert.Equal(t, lastAcceptedTx(t, server).Hash, txId)
 }
 
 func PrepareCancelOfferTxInfo(c *l2Client, offerId int64) string {
@@ -328,7 +380,7 @@ func PrepareCancelOfferTxInfo(c *l2Client, offerId int64) string {
 	}
 
 	ops := new(types.TransactOpts)
-	ops, err := c.fullFillDefaultOps(ops)
+	ops, err := c.fullFillDefaultOps(context.Background(), ops)
 	if err != nil {
 		panic(err)
 	}
@@ -341,20 +393,135 @@ func PrepareCancelOfferTxInfo(c *l2Client, offerId int64) string {
 }
 
 func TestTransferInLayer2(t *testing.T) {
-	l2Client := getSdkClient()
+	l2Client, server := getSdkClient(t)
 
 	txInfo := types.TransferTxReq{
-		ToAccountName: "sher.legend",
+		ToAccountName: "gavin.legend",
 		AssetId:       0,
 		AssetAmount:   big.NewInt(1),
 	}
 	hash, err := l2Client.Transfer(&txInfo, nil)
 	assert.NoError(t, err)
-	fmt.Println("transfer success, tx id=", hash)
+	assert.Equal(t, lastAcceptedTx(t, server).Hash, hash)
+
+	nonce, err := l2Client.GetNextNonce(2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), nonce)
+
+	total, txs, err := l2Client.GetPendingTxsByAccountName("sher.legend")
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), total)
+	if assert.Len(t, txs, 1) {
+		assert.Equal(t, hash, txs[0].Hash)
+	}
+}
+
+func TestTransferAmount(t *testing.T) {
+	l2Client, server := getSdkClient(t)
+
+	value, err := amount.Resolve(l2Client, "0.5 LEG")
+	require.NoError(t, err)
+	gasFee, err := amount.Resolve(l2Client, "0.000000000000001 BNB")
+	require.NoError(t, err)
+	_, err = l2Client.Transfer(&types.TransferTxReq{ToAccountName: "gavin.legend", Amount: value}, &types.TransactOpts{GasFee: gasFee})
+	require.NoError(t, err)
+
+	tx, err := types.ParseTransferTxInfo(lastAcceptedTx(t, server).TxInfo)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), tx.AssetId)
+	assert.Equal(t, big.NewInt(5e17), tx.AssetAmount)
+	assert.Equal(t, int64(0), tx.GasFeeAssetId)
+	assert.Equal(t, big.NewInt(1000), tx.GasFeeAssetAmount)
+
+	_, err = amount.Resolve(l2Client, "0.5 ETH")
+	assert.ErrorIs(t, err, ErrAssetNotFound)
+
+	// an amount without asset fails instead of panicking
+	_, err = l2Client.Transfer(&types.TransferTxReq{ToAccountName: "gavin.legend", Amount: &types.Amount{Value: big.NewInt(100)}}, nil)
+	assert.ErrorIs(t, err, types.ErrAmountWithoutAsset)
+	_, err = l2Client.Withdraw(&types.WithdrawReq{Amount: &types.Amount{Asset: value.Asset}, ToAddress: l1Address}, nil)
+	assert.ErrorIs(t, err, types.ErrAmountWithoutValue)
+	_, err = l2Client.Transfer(&types.TransferTxReq{ToAccountName: "gavin.legend", Amount: value}, &types.TransactOpts{GasFee: &types.Amount{Value: big.NewInt(1000)}})
+	assert.ErrorIs(t, err, types.ErrAmountWithoutAsset)
+	_, err = l2Client.CreateOffer(&types.OfferReq{Amount: &types.Amount{Value: big.NewInt(100)}})
+	assert.ErrorIs(t, err, types.ErrAmountWithoutAsset)
+	_, err = txutils.ConstructTransferTx(l2Client.KeyManager(), &types.TransactOpts{}, &types.TransferTxReq{Amount: &types.Amount{Value: big.NewInt(100)}})
+	assert.Error(t, err)
+}
+
+func TestTransferAccountName(t *testing.T) {
+	l2Client, server := getSdkClient(t)
+
+	_, err := l2Client.Transfer(&types.TransferTxReq{ToAccountName: "Gavin", AssetAmount: big.NewInt(100)}, nil)
+	require.NoError(t, err)
+	tx, err := types.ParseTransferTxInfo(lastAcceptedTx(t, server).TxInfo)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), tx.ToAccountIndex)
+
+	_, err = l2Client.Transfer(&types.TransferTxReq{ToAccountName: "gavin-1.legend", AssetAmount: big.NewInt(100)}, nil)
+	assert.ErrorIs(t, err, zns.ErrInvalidCharacter)
+	assert.Len(t, server.SentTxs(), 1)
+}
+
+func TestTransferNotPackable(t *testing.T) {
+	l2Client, server := getSdkClient(t)
+
+	value, _ := new(big.Int).SetString("123456789012345678", 10)
+	_, err := l2Client.Transfer(&types.TransferTxReq{ToAccountName: "gavin.legend", AssetAmount: value}, nil)
+	assert.ErrorIs(t, err, txutils.ErrAmountNotPackable)
+	assert.Empty(t, server.SentTxs())
+
+	// the amounts of the withdrawals are not packed
+	_, err = l2Client.Withdraw(&types.WithdrawReq{AssetAmount: value, ToAddress: "0x8b2C5A5744F42AA9269BaabDd05933a96D8EF911"}, nil)
+	require.NoError(t, err)
+	withdraw, err := types.ParseWithdrawTxInfo(lastAcceptedTx(t, server).TxInfo)
+	require.NoError(t, err)
+	assert.Equal(t, value, withdraw.AssetAmount)
+	_, err = l2Client.Withdraw(&types.WithdrawReq{AssetAmount: big.NewInt(-1), ToAddress: "0x8b2C5A5744F42AA9269BaabDd05933a96D8EF911"}, nil)
+	assert.ErrorIs(t, err, txutils.ErrPackedOutOfRange)
+
+	ops := &types.TransactOpts{GasFeeAssetAmount: big.NewInt(2049)}
+	_, err = l2Client.Transfer(&types.TransferTxReq{ToAccountName: "gavin.legend", AssetAmount: big.NewInt(100)}, ops)
+	assert.EqualError(t, err, "gas fee is not packable: 2049, use 2040 or 2050 instead")
+	assert.Len(t, server.SentTxs(), 1)
+
+	l2Client.roundUpGasFee = true
+	ops = &types.TransactOpts{GasFeeAssetAmount: big.NewInt(2049)}
+	_, err = l2Client.Transfer(&types.TransferTxReq{ToAccountName: "gavin.legend", AssetAmount: big.NewInt(100)}, ops)
+	require.NoError(t, err)
+	tx, err := types.ParseTransferTxInfo(lastAcceptedTx(t, server).TxInfo)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(2050), tx.GasFeeAssetAmount)
+}
+
+func TestTransferGasFeeTooLow(t *testing.T) {
+	l2Client, server := getSdkClient(t)
+
+	txInfo := types.TransferTxReq{
+		ToAccountName: "gavin.legend",
+		AssetId:       0,
+		AssetAmount:   big.NewInt(1),
+	}
+	_, err := l2Client.Transfer(&txInfo, &types.TransactOpts{GasFeeAssetAmount: big.NewInt(1)})
+	assert.ErrorIs(t, err, ErrInvalidGasFee)
+	assert.Empty(t, server.AcceptedTxs())
+	assert.Len(t, server.SentTxs(), 1)
+}
+
+func TestTransferExpired(t *testing.T) {
+	l2Client, _ := getSdkClient(t)
+
+	txInfo := types.TransferTxReq{
+		ToAccountName: "gavin.legend",
+		AssetId:       0,
+		AssetAmount:   big.NewInt(1),
+	}
+	_, err := l2Client.Transfer(&txInfo, &types.TransactOpts{ExpiredAt: time.Now().Add(-time.Minute).UnixMilli()})
+	assert.ErrorIs(t, err, ErrInvalidExpireTime)
 }
 
 func TestWithdrawBNB(t *testing.T) {
-	sdkClient := getSdkClient()
+	sdkClient, server := getSdkClient(t)
 
 	randomAddress := "0x8b2C5A5744F42AA9269BaabDd05933a96D8EF911"
 
@@ -365,15 +532,12 @@ func TestWithdrawBNB(t *testing.T) {
 	}
 
 	txId, err := sdkClient.Withdraw(&txReq, nil)
-	if err != nil {
-		println(err.Error())
-		return
-	}
-	println("withdraw success, tx id: ", txId)
+	assert.NoError(t, err)
+	assert.Equal(t, lastAcceptedTx(t, server).Hash, txId)
 }
 
 func TestWithdrawBEP20(t *testing.T) {
-	sdkClient := getSdkClient()
+	sdkClient, server := getSdkClient(t)
 
 	randomAddress := "0x8b2C5A5744F42AA9269BaabDd05933a96D8EF911"
 
@@ -384,28 +548,27 @@ func TestWithdrawBEP20(t *testing.T) {
 	}
 
 	txId, err := sdkClient.Withdraw(&txReq, nil)
-	if err != nil {
-		println(err.Error())
-		return
-	}
-	println("withdraw success, tx id: ", txId)
+	assert.NoError(t, err)
+	assert.Equal(t, lastAcceptedTx(t, server).Hash, txId)
 }
 
 func TestWithdrawNft(t *testing.T) {
-	sdkClient := getSdkClient()
+	sdkClient, server := getSdkClient(t)
 
 	randomAddress := "0x8b2C5A5744F42AA9269BaabDd05933a96D8EF911"
 
 	txReq := types.WithdrawNftTxReq{
-		AccountIndex: 4,
+		AccountIndex: 2,
 		NftIndex:     17,
 		ToAddress:    randomAddress,
 	}
 
 	txId, err := sdkClient.WithdrawNft(&txReq, nil)
-	if err != nil {
-		println(err.Error())
-		return
-	}
-	println("withdraw nft success, tx id: ", txId)
+	assert.NoError(t, err)
+	assert.Equal(t, lastAcceptedTx(t, server).Hash, txId)
+
+	// the tx must be signed by the account withdrawing the nft
+	txReq.AccountIndex = 3
+	_, err = sdkClient.WithdrawNft(&txReq, nil)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
 }
@@ -0,0 +1,311 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// WithNonceManager makes the client hand out the nonces of the txs it signs locally, so that txs sent concurrently
+// from the same account get sequential nonces and reach ZkBNB in order. See NonceManager.
+func WithNonceManager() ClientOptionFunc {
+	return func(o *clientOption) {
+		o.nonceManager = true
+	}
+}
+
+// errNotSent marks a handed out nonce whose tx is not sent
+var errNotSent = errors.New("tx not sent")
+
+// NonceState is the nonce of an account as known by a nonce manager.
+type NonceState struct {
+	AccountIndex int64
+	// Local is the next nonce handed out
+	Local int64
+	// Remote is the next nonce of the account according to ZkBNB at the last sync
+	Remote int64
+	// InFlight is the number of handed out nonces whose tx is not answered by ZkBNB yet
+	InFlight int
+	// Synced is false when the next nonce will be synced from ZkBNB first
+	Synced   bool
+	SyncedAt time.Time
+}
+
+// NonceManager hands out sequential nonces per account. The nonce of an account is synced from its next nonce
+// and its pending txs on ZkBNB the first time it is needed, and again after ZkBNB rejected a nonce.
+// Since ZkBNB only accepts the nonces of an account in order, a tx is sent once the txs with a lower nonce
+// are answered. It is safe for concurrent use.
+type NonceManager struct {
+	fetch func(ctx context.Context, accountIndex int64) (int64, error)
+
+	mu       sync.Mutex
+	accounts map[int64]*accountNonce
+}
+
+type accountNonce struct {
+	// syncMu serializes the syncs of the account, it is held without the lock of the manager
+	syncMu   sync.Mutex
+	state    NonceState
+	inFlight map[int64]bool
+	// changed is closed when a tx in flight is answered
+	changed chan struct{}
+}
+
+func newNonceManager(fetch func(ctx context.Context, accountIndex int64) (int64, error)) *NonceManager {
+	return &NonceManager{
+		fetch:    fetch,
+		accounts: make(map[int64]*accountNonce),
+	}
+}
+
+func (m *NonceManager) account(accountIndex int64) *accountNonce {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.accounts[accountIndex]
+	if !ok {
+		a = &accountNonce{
+			state:    NonceState{AccountIndex: accountIndex},
+			inFlight: make(map[int64]bool),
+			changed:  make(chan struct{}),
+		}
+		m.accounts[accountIndex] = a
+	}
+	return a
+}
+
+// Next hands out the next nonce of the account, syncing it from ZkBNB if needed
+func (m *NonceManager) Next(ctx context.Context, accountIndex int64) (int64, error) {
+	a := m.account(accountIndex)
+	a.syncMu.Lock()
+	defer a.syncMu.Unlock()
+
+	m.mu.Lock()
+	synced := a.state.Synced
+	m.mu.Unlock()
+	if !synced {
+		if err := m.sync(ctx, a); err != nil {
+			return 0, err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	nonce := a.state.Local
+	a.state.Local++
+	a.inFlight[nonce] = true
+	return nonce, nil
+}
+
+// Resync syncs the nonce of the account from ZkBNB now
+func (m *NonceManager) Resync(ctx context.Context, accountIndex int64) error {
+	a := m.account(accountIndex)
+	a.syncMu.Lock()
+	defer a.syncMu.Unlock()
+	return m.sync(ctx, a)
+}
+
+// Reset forgets the nonce of the account, it is synced again when needed
+func (m *NonceManager) Reset(accountIndex int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if a, ok := m.accounts[accountIndex]; ok {
+		// wake up the txs waiting for their turn
+		close(a.changed)
+		delete(m.accounts, accountIndex)
+	}
+}
+
+// State returns the nonce state of the account
+func (m *NonceManager) State(accountIndex int64) NonceState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.accounts[accountIndex]
+	if !ok {
+		return NonceState{AccountIndex: accountIndex}
+	}
+	return a.snapshot()
+}
+
+// States returns the nonce states of all the accounts known by the manager, ordered by account index
+func (m *NonceManager) States() []NonceState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	states := make([]NonceState, 0, len(m.accounts))
+	for _, a := range m.accounts {
+		states = append(states, a.snapshot())
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].AccountIndex < states[j].AccountIndex })
+	return states
+}
+
+func (a *accountNonce) snapshot() NonceState {
+	state := a.state
+	state.InFlight = len(a.inFlight)
+	return state
+}
+
+func (m *NonceManager) sync(ctx context.Context, a *accountNonce) error {
+	remote, err := m.fetch(ctx, a.state.AccountIndex)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// the txs in flight may not be seen by ZkBNB yet, their nonces are not handed out again
+	a.state.Local = remote
+	for nonce := range a.inFlight {
+		if nonce >= a.state.Local {
+			a.state.Local = nonce + 1
+		}
+	}
+	a.state.Remote = remote
+	a.state.Synced = true
+	a.state.SyncedAt = time.Now()
+	return nil
+}
+
+// isInFlight reports whether the nonce was handed out by the manager and is not answered yet
+func (m *NonceManager) isInFlight(accountIndex, nonce int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.accounts[accountIndex]
+	return ok && a.inFlight[nonce]
+}
+
+// waitTurn blocks until the txs with a lower nonce handed out by the manager are answered
+func (m *NonceManager) waitTurn(ctx context.Context, accountIndex, nonce int64) error {
+	for {
+		m.mu.Lock()
+		a, ok := m.accounts[accountIndex]
+		if !ok || !a.inFlightBefore(nonce) {
+			m.mu.Unlock()
+			return nil
+		}
+		changed := a.changed
+		m.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+func (a *accountNonce) inFlightBefore(nonce int64) bool {
+	for n := range a.inFlight {
+		if n < nonce {
+			return true
+		}
+	}
+	return false
+}
+
+// done records the answer of ZkBNB to a tx with a handed out nonce
+func (m *NonceManager) done(accountIndex, nonce int64, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.accounts[accountIndex]
+	if !ok || !a.inFlight[nonce] {
+		return
+	}
+	delete(a.inFlight, nonce)
+	close(a.changed)
+	a.changed = make(chan struct{})
+
+	var apiErr *APIError
+	switch {
+	case err == nil:
+		if nonce >= a.state.Remote {
+			a.state.Remote = nonce + 1
+		}
+	case errors.Is(err, errSentLater):
+		// the nonce is taken by a tx signed to be sent later
+	case errors.Is(err, errNotSent),
+		errors.As(err, &apiErr) && apiErr.StatusCode < http.StatusInternalServerError && !errors.Is(err, ErrInvalidNonce):
+		// the tx is rejected, its nonce can be handed out again if it is the last one
+		if a.state.Local == nonce+1 {
+			a.state.Local = nonce
+		} else {
+			a.state.Synced = false
+		}
+	default:
+		// the nonce is wrong or the tx may have landed, sync before the next nonce
+		a.state.Synced = false
+	}
+}
+
+// remoteNonce returns the next nonce of the account on ZkBNB, taking its pending txs into account
+func (c *l2Client) remoteNonce(ctx context.Context, accountIndex int64) (int64, error) {
+	ctx = withStickyAccount(ctx, accountIndex)
+	nonce, err := c.GetNextNonceWithContext(ctx, accountIndex)
+	if err != nil {
+		return 0, err
+	}
+	account, err := c.GetAccountByIndexWithContext(ctx, accountIndex)
+	if err != nil {
+		return 0, err
+	}
+	_, txs, err := c.GetPendingTxsByAccountNameWithContext(ctx, account.Name)
+	if err != nil {
+		return 0, err
+	}
+	for _, tx := range txs {
+		if tx.AccountIndex == accountIndex && tx.Nonce >= nonce {
+			nonce = tx.Nonce + 1
+		}
+	}
+	return nonce, nil
+}
+
+func (c *l2Client) NonceManager() *NonceManager {
+	return c.nonces
+}
+
+// signAndSend signs the tx with ops and sends it. When the nonce was handed out by the nonce manager and ZkBNB
+// rejects it, the nonce is synced and the tx is signed and sent once more with a new nonce.
+func (c *l2Client) signAndSend(ctx context.Context, txType uint32, ops *types.TransactOpts, sign func(ops *types.TransactOpts) (string, error)) (string, error) {
+	managed := c.nonces != nil && c.nonces.isInFlight(ops.FromAccountIndex, ops.Nonce)
+	txHash, err := c.signAndSendOnce(ctx, txType, ops, managed, sign)
+	if !managed || !errors.Is(err, ErrInvalidNonce) {
+		return txHash, err
+	}
+
+	ops.Nonce, err = c.nonces.Next(ctx, ops.FromAccountIndex)
+	if err != nil {
+		return "", err
+	}
+	return c.signAndSendOnce(ctx, txType, ops, true, sign)
+}
+
+func (c *l2Client) signAndSendOnce(ctx context.Context, txType uint32, ops *types.TransactOpts, managed bool, sign func(ops *types.TransactOpts) (string, error)) (string, error) {
+	txInfo, err := sign(ops)
+	if err != nil {
+		if managed {
+			c.nonces.done(ops.FromAccountIndex, ops.Nonce, errNotSent)
+		}
+		return "", err
+	}
+	return c.sendSigned(ctx, txType, ops, txInfo, managed)
+}
+
+// sendSigned sends the signed tx, a tx whose nonce was handed out by the nonce manager is sent once the txs
+// with a lower nonce are answered
+func (c *l2Client) sendSigned(ctx context.Context, txType uint32, ops *types.TransactOpts, txInfo string, managed bool) (string, error) {
+	if managed {
+		if err := c.nonces.waitTurn(ctx, ops.FromAccountIndex, ops.Nonce); err != nil {
+			c.nonces.done(ops.FromAccountIndex, ops.Nonce, errNotSent)
+			return "", err
+		}
+	}
+	txHash, err := c.SendRawTxWithContext(ctx, txType, txInfo)
+	if managed {
+		c.nonces.done(ops.FromAccountIndex, ops.Nonce, err)
+	}
+	return txHash, err
+}
@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/txutils"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+	"github.com/bnb-chain/zkbnb-go-sdk/zkbnbtest"
+)
+
+// newNonceClient returns a client of sher.legend (index 2) with a nonce manager
+func newNonceClient(t *testing.T) (*l2Client, *zkbnbtest.Server) {
+	server := zkbnbtest.NewServer()
+	t.Cleanup(server.Close)
+	keyManager, err := server.AddAccountFromSeed(2, "sher.legend", seed)
+	require.NoError(t, err)
+	_, err = server.AddAccountFromSeed(3, "gavin.legend", buyerSeed)
+	require.NoError(t, err)
+	server.SetBalance(2, 0, big.NewInt(1e18))
+
+	c := NewZkBNBClient(server.URL, WithNonceManager()).(*l2Client)
+	c.SetKeyManager(keyManager)
+	return c, server
+}
+
+func testTransfer() *types.TransferTxReq {
+	return &types.TransferTxReq{ToAccountName: "gavin.legend", AssetAmount: big.NewInt(1)}
+}
+
+// sentNonces returns the nonces of the txs accepted by the server
+func sentNonces(t *testing.T, server *zkbnbtest.Server) []int64 {
+	var nonces []int64
+	for _, sent := range server.AcceptedTxs() {
+		tx, err := txutils.ParseTxInfo(sent.TxType, sent.TxInfo)
+		require.NoError(t, err)
+		nonces = append(nonces, tx.GetNonce())
+	}
+	return nonces
+}
+
+func TestNonceManagerConcurrentSends(t *testing.T) {
+	c, server := newNonceClient(t)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 20)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = c.Transfer(testTransfer(), nil)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	assert.ElementsMatch(t, []int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19}, sentNonces(t, server))
+	state := c.NonceManager().State(2)
+	assert.Equal(t, int64(20), state.Local)
+	assert.Equal(t, int64(20), state.Remote)
+	assert.Zero(t, state.InFlight)
+	assert.True(t, state.Synced)
+}
+
+func TestNonceManagerResyncsOnInvalidNonce(t *testing.T) {
+	c, server := newNonceClient(t)
+
+	_, err := c.Transfer(testTransfer(), nil)
+	require.NoError(t, err)
+
+	// another sender used the nonces of the account
+	server.SetNonce(2, 10)
+	_, err = c.Transfer(testTransfer(), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []int64{0, 10}, sentNonces(t, server))
+	sent := server.SentTxs()
+	if assert.Len(t, sent, 3) {
+		assert.Error(t, sent[1].Err, "the nonce 1 is rejected")
+	}
+	state := c.NonceManager().State(2)
+	assert.Equal(t, int64(11), state.Local)
+	assert.Equal(t, int64(11), state.Remote)
+}
+
+func TestNonceManagerReleasesRejectedNonce(t *testing.T) {
+	c, server := newNonceClient(t)
+
+	_, err := c.Transfer(testTransfer(), &types.TransactOpts{GasFeeAssetAmount: big.NewInt(1)})
+	assert.ErrorIs(t, err, ErrInvalidGasFee)
+	assert.Equal(t, int64(0), c.NonceManager().State(2).Local)
+
+	_, err = c.Transfer(testTransfer(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{0}, sentNonces(t, server))
+}
+
+func TestNonceManagerCountsPendingTxs(t *testing.T) {
+	c, server := newNonceClient(t)
+
+	// a client without nonce manager sends 2 txs, then the next nonce api lags behind the pending txs
+	other := NewZkBNBClient(server.URL)
+	other.SetKeyManager(c.KeyManager())
+	for i := 0; i < 2; i++ {
+		_, err := other.Transfer(testTransfer(), nil)
+		require.NoError(t, err)
+	}
+	server.Handle("/api/v1/nextNonce", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"nonce":0}`))
+	})
+
+	require.NoError(t, c.NonceManager().Resync(context.Background(), 2))
+	state := c.NonceManager().State(2)
+	assert.Equal(t, int64(2), state.Remote)
+	assert.Equal(t, int64(2), state.Local)
+
+	states := c.NonceManager().States()
+	if assert.Len(t, states, 1) {
+		assert.Equal(t, int64(2), states[0].AccountIndex)
+	}
+	c.NonceManager().Reset(2)
+	assert.False(t, c.NonceManager().State(2).Synced)
+}
+
+func TestNonceManagerDisabled(t *testing.T) {
+	c := NewZkBNBClient("http://127.0.0.1:1")
+	assert.Nil(t, c.NonceManager())
+}
+
+func TestNonceManagerResyncWithTxsInFlight(t *testing.T) {
+	// ZkBNB does not see the txs in flight yet
+	m := newNonceManager(func(ctx context.Context, accountIndex int64) (int64, error) {
+		return 0, nil
+	})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	nonces := make([]int64, 20)
+	for i := range nonces {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			var err error
+			nonces[i], err = m.Next(ctx, 2)
+			assert.NoError(t, err)
+		}(i)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, m.Resync(ctx, 2))
+		}()
+	}
+	wg.Wait()
+	assert.ElementsMatch(t, []int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19}, nonces)
+
+	m.done(2, 19, nil)
+	require.NoError(t, m.Resync(ctx, 2))
+	state := m.State(2)
+	assert.Equal(t, int64(19), state.Local)
+	assert.Equal(t, int64(0), state.Remote)
+	assert.Equal(t, 19, state.InFlight)
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/txutils"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+const (
+	defaultOfferExpireTime = 7 * 24 * time.Hour
+	// defaultTreasuryRate is the share of the offer amount paid to the treasury, in 1/10000
+	defaultTreasuryRate = 200
+)
+
+func (c *l2Client) SignOffer(offer *types.OfferTxInfo) (*types.SignedOffer, error) {
+	if c.keyManager == nil {
+		return nil, fmt.Errorf("key manager is nil")
+	}
+
+	txInfo, err := txutils.ConstructOfferTx(c.keyManager, offer)
+	if err != nil {
+		return nil, err
+	}
+	signed := &types.SignedOffer{}
+	if err := signed.Unmarshal(txInfo); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+func (c *l2Client) CreateOffer(tx *types.OfferReq) (*types.SignedOffer, error) {
+	return c.CreateOfferWithContext(context.Background(), tx)
+}
+
+func (c *l2Client) CreateOfferWithContext(ctx context.Context, tx *types.OfferReq) (*types.SignedOffer, error) {
+	if c.keyManager == nil {
+		return nil, fmt.Errorf("key manager is nil")
+	}
+
+	if tx.Amount != nil {
+		if err := tx.Amount.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	assetId, assetAmount := tx.AssetAndAmount()
+	offer := &types.OfferTxInfo{
+		Type:         tx.Type,
+		OfferId:      tx.OfferId,
+		AccountIndex: tx.AccountIndex,
+		NftIndex:     tx.NftIndex,
+		AssetId:      assetId,
+		AssetAmount:  assetAmount,
+		ListedAt:     tx.ListedAt,
+		ExpiredAt:    tx.ExpiredAt,
+		TreasuryRate: c.treasuryRate,
+	}
+	if tx.TreasuryRate != nil {
+		offer.TreasuryRate = *tx.TreasuryRate
+	}
+	if offer.AccountIndex == 0 {
+		l2Account, err := c.GetAccountByPkWithContext(ctx, hex.EncodeToString(c.keyManager.PubKey().Bytes()))
+		if err != nil {
+			return nil, err
+		}
+		offer.AccountIndex = l2Account.Index
+	}
+	if offer.OfferId == 0 {
+		offerId, err := c.nextOfferId(ctx, offer.AccountIndex)
+		if err != nil {
+			return nil, err
+		}
+		offer.OfferId = offerId
+	}
+	if offer.ListedAt == 0 {
+		offer.ListedAt = time.Now().UnixMilli()
+	}
+	if offer.ExpiredAt == 0 {
+		offer.ExpiredAt = time.UnixMilli(offer.ListedAt).Add(defaultOfferExpireTime).UnixMilli()
+	}
+	return c.SignOffer(offer)
+}
+
+// nextOfferId allocates an offer id of the account, the ids allocated locally are not reused
+// even if the offers are not matched yet.
+func (c *l2Client) nextOfferId(ctx context.Context, accountIndex int64) (int64, error) {
+	maxOfferId, err := c.GetMaxOfferIdWithContext(ctx, accountIndex)
+	if err != nil {
+		return 0, err
+	}
+
+	c.offerIdsMu.Lock()
+	defer c.offerIdsMu.Unlock()
+	if c.offerIds == nil {
+		c.offerIds = make(map[int64]int64)
+	}
+	offerId := int64(maxOfferId) + 1
+	if allocated, ok := c.offerIds[accountIndex]; ok && allocated >= offerId {
+		offerId = allocated + 1
+	}
+	c.offerIds[accountIndex] = offerId
+	return offerId, nil
+}
@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/accounts"
+	"github.com/bnb-chain/zkbnb-go-sdk/txutils"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+func newOfferServer(t *testing.T, form *url.Values) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/account":
+			assert.Equal(t, "pk", r.URL.Query().Get("by"))
+			_, _ = w.Write([]byte(`{"index":2,"name":"sher.legend"}`))
+		case "/api/v1/maxOfferId":
+			assert.Equal(t, "2", r.URL.Query().Get("account_index"))
+			_, _ = w.Write([]byte(`{"offer_id":4}`))
+		case "/api/v1/gasAccount":
+			_, _ = w.Write([]byte(`{"status":1,"index":1,"name":"gas.legend"}`))
+		case "/api/v1/nextNonce":
+			_, _ = w.Write([]byte(`{"nonce":5}`))
+		case "/api/v1/gasFee":
+			_, _ = w.Write([]byte(`{"gas_fee":"1000"}`))
+		case "/api/v1/sendTx":
+			assert.NoError(t, r.ParseForm())
+			*form = r.PostForm
+			_, _ = w.Write([]byte(`{"tx_hash":"hash"}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+}
+
+func TestCreateOffer(t *testing.T) {
+	var form url.Values
+	server := newOfferServer(t, &form)
+	defer server.Close()
+
+	c := NewZkBNBClient(server.URL)
+	_, err := c.CreateOffer(&types.OfferReq{})
+	assert.EqualError(t, err, "key manager is nil")
+
+	keyManager, err := accounts.NewSeedKeyManager(seed)
+	assert.NoError(t, err)
+	c.SetKeyManager(keyManager)
+
+	start := time.Now().UnixMilli()
+	sellOffer, err := c.CreateOffer(&types.OfferReq{
+		Type:        types.SellOfferType,
+		NftIndex:    3,
+		AssetAmount: big.NewInt(10000),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), sellOffer.AccountIndex)
+	assert.Equal(t, int64(5), sellOffer.OfferId)
+	assert.GreaterOrEqual(t, sellOffer.ListedAt, start)
+	assert.Equal(t, sellOffer.ListedAt+defaultOfferExpireTime.Milliseconds(), sellOffer.ExpiredAt)
+	assert.Equal(t, int64(defaultTreasuryRate), sellOffer.TreasuryRate)
+	assert.NoError(t, txutils.VerifyOfferTxSig(testPk(t), &sellOffer.OfferTxInfo))
+
+	// the ids are not reused before the offers are matched
+	buyOffer, err := c.CreateOfferWithContext(context.Background(), &types.OfferReq{
+		Type:         types.BuyOfferType,
+		NftIndex:     3,
+		AssetAmount:  big.NewInt(10000),
+		TreasuryRate: types.NewTreasuryRate(100),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), buyOffer.OfferId)
+	assert.Equal(t, int64(100), buyOffer.TreasuryRate)
+
+	// the signed offers can be shared as json
+	sellOfferJSON, err := sellOffer.Marshal()
+	assert.NoError(t, err)
+	sharedSellOffer := &types.SignedOffer{}
+	assert.NoError(t, sharedSellOffer.Unmarshal(sellOfferJSON))
+	assert.Equal(t, sellOffer, sharedSellOffer)
+	assert.NoError(t, txutils.VerifyTxSignature(types.TxTypeOffer, sellOfferJSON, testPk(t)))
+
+	_, err = c.AtomicMatch(&types.AtomicMatchTxReq{BuyOffer: buyOffer, SellOffer: sharedSellOffer}, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, txutils.VerifyTxSignature(types.TxTypeAtomicMatch, form.Get("tx_info"), testPk(t), testPk(t), testPk(t)))
+}
+
+func TestCreateOfferTreasuryRate(t *testing.T) {
+	var form url.Values
+	server := newOfferServer(t, &form)
+	defer server.Close()
+
+	keyManager, err := accounts.NewSeedKeyManager(seed)
+	assert.NoError(t, err)
+	c := NewZkBNBClient(server.URL, WithOfferTreasuryRate(50))
+	c.SetKeyManager(keyManager)
+
+	offer, err := c.CreateOffer(&types.OfferReq{Type: types.SellOfferType, NftIndex: 3, AssetAmount: big.NewInt(10000)})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(50), offer.TreasuryRate)
+
+	// an explicit 0 is not replaced by the default
+	offer, err = c.CreateOffer(&types.OfferReq{
+		Type:         types.SellOfferType,
+		NftIndex:     3,
+		AssetAmount:  big.NewInt(10000),
+		TreasuryRate: types.NewTreasuryRate(0),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), offer.TreasuryRate)
+	assert.NoError(t, txutils.VerifyOfferTxSig(testPk(t), &offer.OfferTxInfo))
+}
+
+func TestSignOffer(t *testing.T) {
+	c := NewZkBNBClient("http://localhost")
+	keyManager, err := accounts.NewSeedKeyManager(seed)
+	assert.NoError(t, err)
+	c.SetKeyManager(keyManager)
+
+	offer := &types.OfferTxInfo{
+		Type:         types.BuyOfferType,
+		OfferId:      1,
+		AccountIndex: 2,
+		NftIndex:     3,
+		AssetAmount:  big.NewInt(10000),
+		ListedAt:     time.Now().UnixMilli(),
+		ExpiredAt:    time.Now().Add(time.Hour).UnixMilli(),
+		TreasuryRate: 200,
+	}
+	signed, err := c.SignOffer(offer)
+	assert.NoError(t, err)
+	assert.Nil(t, offer.Sig)
+	assert.NotEmpty(t, signed.Sig)
+	assert.NoError(t, txutils.VerifyOfferTxSig(testPk(t), &signed.OfferTxInfo))
+}
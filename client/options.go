@@ -0,0 +1,144 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type clientOption struct {
+	httpClient   *http.Client
+	roundTripper http.RoundTripper
+	timeout      time.Duration
+	rootCAs      *x509.CertPool
+	certificates []tls.Certificate
+	insecure     bool
+	headers      http.Header
+	retryPolicy  *RetryPolicy
+	interceptors []Interceptor
+
+	tracerProvider trace.TracerProvider
+	registerer     prometheus.Registerer
+
+	nonceManager  bool
+	roundUpGasFee bool
+	batchEndpoint bool
+	treasuryRate  *int64
+
+	endpoints           []string
+	healthCheckInterval time.Duration
+	maxHeightLag        int64
+}
+
+type ClientOptionFunc func(*clientOption)
+
+// WithHttpClient uses the given http client for all requests, the tls and timeout options are ignored
+func WithHttpClient(httpClient *http.Client) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithRoundTripper uses the given round tripper as the transport of the http client, the tls options are ignored
+func WithRoundTripper(roundTripper http.RoundTripper) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.roundTripper = roundTripper
+	}
+}
+
+// WithTimeout sets the timeout of each http request, including reading the response body
+func WithTimeout(timeout time.Duration) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.timeout = timeout
+	}
+}
+
+// WithRootCAs sets the certificate pool used to verify the server certificates
+func WithRootCAs(rootCAs *x509.CertPool) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.rootCAs = rootCAs
+	}
+}
+
+// WithClientCertificates sets the client certificates presented to the server for mutual tls
+func WithClientCertificates(certificates ...tls.Certificate) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.certificates = append(o.certificates, certificates...)
+	}
+}
+
+// WithInsecureSkipVerify disables the verification of the server certificates, only use it for testing
+func WithInsecureSkipVerify() ClientOptionFunc {
+	return func(o *clientOption) {
+		o.insecure = true
+	}
+}
+
+// WithHeader adds a header to every request, e.g. an api key
+func WithHeader(key, value string) ClientOptionFunc {
+	return func(o *clientOption) {
+		if o.headers == nil {
+			o.headers = make(http.Header)
+		}
+		o.headers.Add(key, value)
+	}
+}
+
+// WithGasFeeRoundUp rounds the gas fees up to the nearest fee of the packed fee format of ZkBNB, instead of
+// failing with txutils.ErrFeeNotPackable
+func WithGasFeeRoundUp() ClientOptionFunc {
+	return func(o *clientOption) {
+		o.roundUpGasFee = true
+	}
+}
+
+// WithOfferTreasuryRate sets the treasury rate of the offers created without one, in 1/10000.
+// It should be the treasury rate of the ZkBNB platform, 200 by default.
+func WithOfferTreasuryRate(rate int64) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.treasuryRate = &rate
+	}
+}
+
+func (o *clientOption) buildHttpClient() *http.Client {
+	if o.httpClient != nil {
+		return o.httpClient
+	}
+	if o.roundTripper == nil && o.timeout == 0 && o.rootCAs == nil && len(o.certificates) == 0 && !o.insecure {
+		return nil
+	}
+
+	roundTripper := o.roundTripper
+	if roundTripper == nil {
+		roundTripper = &http.Transport{
+			DialContext:         dialer.DialContext,
+			MaxConnsPerHost:     1000,
+			MaxIdleConnsPerHost: 100,
+			IdleConnTimeout:     10 * time.Second,
+			TLSClientConfig: &tls.Config{
+				RootCAs:            o.rootCAs,
+				Certificates:       o.certificates,
+				InsecureSkipVerify: o.insecure,
+			},
+		}
+	}
+	timeout := o.timeout
+	if timeout == 0 {
+		timeout = HttpClient.Timeout
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: roundTripper,
+	}
+}
+
+type l1ClientOption struct {
+	tracerProvider trace.TracerProvider
+	registerer     prometheus.Registerer
+}
+
+type L1ClientOptionFunc func(*l1ClientOption)
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTLSHeightServer(t *testing.T) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"code":401,"message":"unauthorized"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"height":10}`))
+	}))
+}
+
+func TestClientVerifiesCertificatesByDefault(t *testing.T) {
+	server := newTLSHeightServer(t)
+	defer server.Close()
+
+	c := NewZkBNBClient(server.URL, WithHeader("X-Api-Key", "secret"))
+	_, err := c.GetCurrentHeight()
+	assert.Error(t, err)
+}
+
+func TestClientWithRootCAs(t *testing.T) {
+	server := newTLSHeightServer(t)
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	c := NewZkBNBClient(server.URL, WithRootCAs(pool), WithHeader("X-Api-Key", "secret"))
+	height, err := c.GetCurrentHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), height)
+
+	c = NewZkBNBClient(server.URL, WithRootCAs(pool))
+	_, err = c.GetCurrentHeight()
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+}
+
+func TestClientWithInsecureSkipVerify(t *testing.T) {
+	server := newTLSHeightServer(t)
+	defer server.Close()
+
+	c := NewZkBNBClient(server.URL, WithInsecureSkipVerify(), WithHeader("X-Api-Key", "secret"))
+	height, err := c.GetCurrentHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), height)
+}
+
+func TestClientWithHttpClientAndTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"height":10}`))
+	}))
+	defer server.Close()
+
+	c := NewZkBNBClient(server.URL, WithTimeout(50*time.Millisecond))
+	_, err := c.GetCurrentHeight()
+	assert.Error(t, err)
+
+	c = NewZkBNBClient(server.URL, WithHttpClient(&http.Client{Timeout: time.Second}))
+	height, err := c.GetCurrentHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), height)
+}
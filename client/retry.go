@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// defaultRetryStatusCodes are the http status codes of transient api errors.
+var defaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy configures how the l2 client retries failed api calls.
+type RetryPolicy struct {
+	// MaxAttempts is the max number of attempts of a call, including the first one
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts
+	MaxBackoff time.Duration
+	// Multiplier is the factor the delay grows by after each retry
+	Multiplier float64
+	// Jitter is the fraction of the delay that is randomized, in [0, 1]
+	Jitter float64
+	// RetryStatusCodes are the http status codes considered transient by the default classifier
+	RetryStatusCodes []int
+	// Retryable overrides the default classifier if set, it decides whether a failed attempt is retried
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy returns a policy with 4 attempts and exponential backoff starting at 200ms.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:      4,
+		InitialBackoff:   200 * time.Millisecond,
+		MaxBackoff:       5 * time.Second,
+		Multiplier:       2,
+		Jitter:           0.2,
+		RetryStatusCodes: defaultRetryStatusCodes,
+	}
+}
+
+// WithRetryPolicy enables retries of failed api calls with the given policy
+func WithRetryPolicy(policy *RetryPolicy) ClientOptionFunc {
+	return func(o *clientOption) {
+		o.retryPolicy = policy
+	}
+}
+
+func (p *RetryPolicy) isRetryable(err error) bool {
+	var permanentErr *permanentError
+	if errors.As(err, &permanentErr) {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return isTransientError(err, p.RetryStatusCodes)
+}
+
+// isTransientError reports whether err is a network error or an api error with one of the status codes.
+func isTransientError(err error, statusCodes []int) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		for _, code := range statusCodes {
+			if apiErr.StatusCode == code {
+				return true
+			}
+		}
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET)
+}
+
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	delay := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(retry))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		delay -= delay * p.Jitter * rand.Float64()
+	}
+	return time.Duration(delay)
+}
+
+// permanentError stops the retries of a call, the wrapped error is returned to the caller.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// do runs call until it succeeds, returns a non retryable error or the attempts are exhausted.
+// A nil policy runs call only once. When ctx is done during a backoff, the error of ctx is returned with the last error.
+func (p *RetryPolicy) do(ctx context.Context, call func(attempt int) error) error {
+	if p == nil || p.MaxAttempts <= 1 {
+		return unwrapPermanent(call(0))
+	}
+
+	var err error
+	for attempt := 0; attempt < p.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(p.backoff(attempt - 1))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+			case <-timer.C:
+			}
+		}
+		err = call(attempt)
+		if err == nil || !p.isRetryable(err) {
+			return unwrapPermanent(err)
+		}
+	}
+	return err
+}
+
+func unwrapPermanent(err error) error {
+	var permanentErr *permanentError
+	if errors.As(err, &permanentErr) {
+		return permanentErr.err
+	}
+	return err
+}
@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/accounts"
+	"github.com/bnb-chain/zkbnb-go-sdk/txutils"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+func testRetryPolicy() *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	return policy
+}
+
+func signedTestTransfer(t *testing.T) string {
+	keyManager, err := accounts.NewSeedKeyManager(seed)
+	assert.NoError(t, err)
+	toAccountNameHash, err := txutils.AccountNameHash("gavin.legend")
+	assert.NoError(t, err)
+	ops := &types.TransactOpts{
+		FromAccountIndex:  2,
+		ToAccountIndex:    3,
+		ToAccountNameHash: toAccountNameHash,
+		GasAccountIndex:   1,
+		GasFeeAssetId:     0,
+		GasFeeAssetAmount: big.NewInt(1000),
+		CallDataHash:      mimc.NewMiMC().Sum(nil),
+		ExpiredAt:         time.Now().Add(time.Hour).UnixMilli(),
+		Nonce:             1,
+	}
+	txInfo, err := txutils.ConstructTransferTx(keyManager, ops, &types.TransferTxReq{
+		ToAccountName: "gavin.legend",
+		AssetId:       0,
+		AssetAmount:   big.NewInt(100),
+	})
+	assert.NoError(t, err)
+	return txInfo
+}
+
+func TestRetryTransientErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"height":7}`))
+	}))
+	defer server.Close()
+
+	c := NewZkBNBClient(server.URL, WithRetryPolicy(testRetryPolicy()))
+	height, err := c.GetCurrentHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), height)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestRetryStopsOnPermanentErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"code":29404,"message":"account not found"}`))
+	}))
+	defer server.Close()
+
+	c := NewZkBNBClient(server.URL, WithRetryPolicy(testRetryPolicy()))
+	_, err := c.GetAccountByName("sher.legend")
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestRetryStopsWhenContextDone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Hour
+	c := NewZkBNBClient(server.URL, WithRetryPolicy(policy))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.GetCurrentHeightWithContext(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "status 503")
+}
+
+func TestSendRawTxDoesNotResubmitLandedTx(t *testing.T) {
+	txInfo := signedTestTransfer(t)
+	txHash, err := txutils.TxHash(types.TxTypeTransfer, txInfo)
+	assert.NoError(t, err)
+
+	var sends int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/sendTx":
+			atomic.AddInt32(&sends, 1)
+			// the tx is accepted but the response is lost
+			w.WriteHeader(http.StatusBadGateway)
+		case "/api/v1/tx":
+			assert.Equal(t, txHash, r.URL.Query().Get("hash"))
+			_, _ = w.Write([]byte(`{"hash":"` + txHash + `"}`))
+		}
+	}))
+	defer server.Close()
+
+	c := NewZkBNBClient(server.URL, WithRetryPolicy(testRetryPolicy()))
+	hash, err := c.SendRawTx(types.TxTypeTransfer, txInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, txHash, hash)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&sends))
+}
+
+func TestSendRawTxResubmitsUnknownTx(t *testing.T) {
+	txInfo := signedTestTransfer(t)
+
+	var sends int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/sendTx":
+			if atomic.AddInt32(&sends, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(`{"tx_hash":"0xabc"}`))
+		case "/api/v1/tx":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":29404,"message":"tx not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	c := NewZkBNBClient(server.URL, WithRetryPolicy(testRetryPolicy()))
+	hash, err := c.SendRawTx(types.TxTypeTransfer, txInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, "0xabc", hash)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&sends))
+}
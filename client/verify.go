@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/txutils"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// TxVerification is the result of checking the signature of a tx against the key of its signer.
+type TxVerification struct {
+	TxHash string
+	TxType int64
+	// Signed is false for the l1 txs, which have no l2 signature
+	Signed bool
+	// SignerIndex and SignerPk are the account which signed the tx and its public key
+	SignerIndex int64
+	SignerPk    string
+	// OfferSignerPks are the public keys of the buyer and the seller of an atomic match tx
+	OfferSignerPks []string
+	// Valid reports whether the signatures match the keys, Err is the reason when they don't
+	Valid bool
+	Err   error
+}
+
+// AccountAudit is the result of checking the signatures of all the txs of an account.
+type AccountAudit struct {
+	AccountIndex int64
+	Txs          []*TxVerification
+	Valid        int
+	Invalid      int
+	Unsigned     int
+}
+
+// Invalids returns the verifications of the txs with an invalid signature
+func (a *AccountAudit) Invalids() []*TxVerification {
+	var invalids []*TxVerification
+	for _, verification := range a.Txs {
+		if verification.Signed && !verification.Valid {
+			invalids = append(invalids, verification)
+		}
+	}
+	return invalids
+}
+
+func (c *l2Client) VerifyTxByHash(ctx context.Context, hash string) (*TxVerification, error) {
+	tx, err := c.GetTxWithContext(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return c.VerifyTx(ctx, &tx.Tx)
+}
+
+func (c *l2Client) VerifyTx(ctx context.Context, tx *types.Tx) (*TxVerification, error) {
+	return c.verifyTx(ctx, tx, make(map[int64]string))
+}
+
+func (c *l2Client) AuditAccountTxs(ctx context.Context, accountIndex int64, options ...IteratorOptionFunc) (*AccountAudit, error) {
+	audit := &AccountAudit{AccountIndex: accountIndex}
+	pks := make(map[int64]string)
+	it := c.IterateTxsByAccountIndex(accountIndex, options...)
+	for it.Next(ctx) {
+		verification, err := c.verifyTx(ctx, it.Tx(), pks)
+		if err != nil {
+			return nil, err
+		}
+		audit.Txs = append(audit.Txs, verification)
+		switch {
+		case !verification.Signed:
+			audit.Unsigned++
+		case verification.Valid:
+			audit.Valid++
+		default:
+			audit.Invalid++
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return audit, nil
+}
+
+// verifyTx checks the signatures of tx, pks caches the public keys of the accounts by index.
+// An error is returned only if the keys of the signers can't be fetched.
+func (c *l2Client) verifyTx(ctx context.Context, tx *types.Tx, pks map[int64]string) (*TxVerification, error) {
+	verification := &TxVerification{TxHash: tx.Hash, TxType: tx.Type}
+	txInfo, err := txutils.ParseTxInfo(uint32(tx.Type), tx.Info)
+	if err != nil {
+		if _, decodeErr := tx.DecodeInfo(); decodeErr == nil {
+			// a l1 tx
+			return verification, nil
+		}
+		verification.Signed = true
+		verification.Err = fmt.Errorf("decode tx info: %w", err)
+		return verification, nil
+	}
+
+	verification.Signed = true
+	verification.SignerIndex = txInfo.GetFromAccountIndex()
+	if verification.SignerPk, err = c.accountPk(ctx, verification.SignerIndex, pks); err != nil {
+		return nil, err
+	}
+	if atomicMatch, ok := txInfo.(*types.AtomicMatchTxInfo); ok && atomicMatch.BuyOffer != nil && atomicMatch.SellOffer != nil {
+		for _, offerAccountIndex := range []int64{atomicMatch.BuyOffer.AccountIndex, atomicMatch.SellOffer.AccountIndex} {
+			pk, err := c.accountPk(ctx, offerAccountIndex, pks)
+			if err != nil {
+				return nil, err
+			}
+			verification.OfferSignerPks = append(verification.OfferSignerPks, pk)
+		}
+	}
+
+	verification.Err = txutils.VerifyTxSignature(uint32(tx.Type), tx.Info, verification.SignerPk, verification.OfferSignerPks...)
+	verification.Valid = verification.Err == nil
+	return verification, nil
+}
+
+func (c *l2Client) accountPk(ctx context.Context, accountIndex int64, pks map[int64]string) (string, error) {
+	if pk, ok := pks[accountIndex]; ok {
+		return pk, nil
+	}
+	account, err := c.GetAccountByIndexWithContext(ctx, accountIndex)
+	if err != nil {
+		return "", fmt.Errorf("get the key of account %d: %w", accountIndex, err)
+	}
+	pks[accountIndex] = account.Pk
+	return account.Pk, nil
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/accounts"
+	"github.com/bnb-chain/zkbnb-go-sdk/txutils"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+func newAuditServer(t *testing.T, pks map[int64]string, txs []*types.Tx) (*httptest.Server, *int) {
+	var accountRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/api/v1/account":
+			accountRequests++
+			for index, pk := range pks {
+				if q.Get("value") == strconv.FormatInt(index, 10) {
+					_ = json.NewEncoder(w).Encode(&types.Account{Index: index, Pk: pk})
+					return
+				}
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":29404,"message":"account not found"}`))
+		case "/api/v1/tx":
+			for _, tx := range txs {
+				if tx.Hash == q.Get("hash") {
+					_ = json.NewEncoder(w).Encode(&types.EnrichedTx{Tx: *tx})
+					return
+				}
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":29404,"message":"tx not found"}`))
+		case "/api/v1/accountTxs":
+			_ = json.NewEncoder(w).Encode(&types.Txs{Total: uint32(len(txs)), Txs: txs})
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	return server, &accountRequests
+}
+
+func testPk(t *testing.T) string {
+	keyManager, err := accounts.NewSeedKeyManager(seed)
+	assert.NoError(t, err)
+	return hex.EncodeToString(keyManager.PubKey().Bytes())
+}
+
+func TestVerifyTx(t *testing.T) {
+	transfer := signedTestTransfer(t)
+	hash, err := txutils.TxHash(types.TxTypeTransfer, transfer)
+	assert.NoError(t, err)
+	txs := []*types.Tx{{Hash: hash, Type: types.TxTypeTransfer, Info: transfer}}
+	server, _ := newAuditServer(t, map[int64]string{2: testPk(t)}, txs)
+	defer server.Close()
+
+	c := NewZkBNBClient(server.URL)
+	verification, err := c.VerifyTxByHash(context.Background(), hash)
+	assert.NoError(t, err)
+	assert.True(t, verification.Signed)
+	assert.True(t, verification.Valid)
+	assert.NoError(t, verification.Err)
+	assert.Equal(t, int64(2), verification.SignerIndex)
+	assert.Equal(t, testPk(t), verification.SignerPk)
+
+	_, err = c.VerifyTxByHash(context.Background(), "unknown")
+	assert.ErrorIs(t, err, ErrTxNotFound)
+
+	// the signer must exist
+	_, err = c.VerifyTx(context.Background(), &types.Tx{Type: types.TxTypeTransfer, Info: strings.Replace(transfer, `"FromAccountIndex":2`, `"FromAccountIndex":7`, 1)})
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+}
+
+func TestAuditAccountTxs(t *testing.T) {
+	transfer := signedTestTransfer(t)
+	tampered := strings.Replace(transfer, `"AssetAmount":100`, `"AssetAmount":1000`, 1)
+	assert.NotEqual(t, transfer, tampered)
+	deposit, err := types.MarshalTxInfo(&types.DepositTxInfo{TxType: types.TxTypeDeposit, AccountIndex: 2})
+	assert.NoError(t, err)
+
+	txs := []*types.Tx{
+		{Hash: "a", Type: types.TxTypeTransfer, Info: transfer},
+		{Hash: "b", Type: types.TxTypeTransfer, Info: tampered},
+		{Hash: "c", Type: types.TxTypeDeposit, Info: deposit},
+		{Hash: "d", Type: types.TxTypeTransfer, Info: "corrupted"},
+	}
+	server, accountRequests := newAuditServer(t, map[int64]string{2: testPk(t)}, txs)
+	defer server.Close()
+
+	c := NewZkBNBClient(server.URL)
+	audit, err := c.AuditAccountTxs(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Len(t, audit.Txs, 4)
+	assert.Equal(t, 1, audit.Valid)
+	assert.Equal(t, 2, audit.Invalid)
+	assert.Equal(t, 1, audit.Unsigned)
+	assert.False(t, audit.Txs[2].Signed)
+
+	invalids := audit.Invalids()
+	assert.Len(t, invalids, 2)
+	assert.Equal(t, "b", invalids[0].TxHash)
+	assert.ErrorIs(t, invalids[0].Err, txutils.ErrInvalidSignature)
+	assert.Equal(t, "d", invalids[1].TxHash)
+	assert.Error(t, invalids[1].Err)
+
+	// the keys are fetched once per account
+	assert.Equal(t, 1, *accountRequests)
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+var (
+	// ErrTxFailed is wrapped by the *TxFailedError returned when a waited tx fails
+	ErrTxFailed = errors.New("tx failed")
+	// ErrTxExpired is wrapped by the *TxExpiredError returned when a waited tx expires before being executed
+	ErrTxExpired = errors.New("tx expired")
+)
+
+// TxFailedError is returned by WaitForTx when the tx is failed.
+type TxFailedError struct {
+	Tx *types.EnrichedTx
+}
+
+func (e *TxFailedError) Error() string {
+	return fmt.Sprintf("tx %s failed", e.Tx.Hash)
+}
+
+func (e *TxFailedError) Unwrap() error {
+	return ErrTxFailed
+}
+
+// TxExpiredError is returned by WaitForTx when the tx is still pending after its expire time.
+type TxExpiredError struct {
+	Tx *types.EnrichedTx
+}
+
+func (e *TxExpiredError) Error() string {
+	return fmt.Sprintf("tx %s expired at %s", e.Tx.Hash, time.UnixMilli(e.Tx.ExpiredAt).Format(time.RFC3339))
+}
+
+func (e *TxExpiredError) Unwrap() error {
+	return ErrTxExpired
+}
+
+type waitOption struct {
+	pollInterval    time.Duration
+	maxPollInterval time.Duration
+	concurrency     int
+}
+
+type WaitOptionFunc func(*waitOption)
+
+// WaitWithPollInterval sets the delay before the first poll, 500ms by default, the delay grows up to maxPollInterval
+func WaitWithPollInterval(pollInterval, maxPollInterval time.Duration) WaitOptionFunc {
+	return func(o *waitOption) {
+		o.pollInterval = pollInterval
+		o.maxPollInterval = maxPollInterval
+	}
+}
+
+// WaitWithConcurrency sets the max number of txs WaitForTxs polls at the same time, 8 by default
+func WaitWithConcurrency(concurrency int) WaitOptionFunc {
+	return func(o *waitOption) {
+		o.concurrency = concurrency
+	}
+}
+
+func newWaitOption(options []WaitOptionFunc) *waitOption {
+	opt := &waitOption{
+		pollInterval:    500 * time.Millisecond,
+		maxPollInterval: 5 * time.Second,
+		concurrency:     8,
+	}
+	for _, f := range options {
+		f(opt)
+	}
+	if opt.concurrency <= 0 {
+		opt.concurrency = 1
+	}
+	return opt
+}
+
+// WaitResult is the outcome of waiting for one of the txs of WaitForTxs.
+type WaitResult struct {
+	Hash string
+	Tx   *types.EnrichedTx
+	Err  error
+}
+
+func (c *l2Client) WaitForTx(ctx context.Context, hash string, targetStatus types.TxStatus, options ...WaitOptionFunc) (*types.EnrichedTx, error) {
+	return c.waitForTx(ctx, hash, targetStatus, newWaitOption(options))
+}
+
+func (c *l2Client) WaitForTxs(ctx context.Context, hashes []string, targetStatus types.TxStatus, options ...WaitOptionFunc) []*WaitResult {
+	opt := newWaitOption(options)
+	results := make([]*WaitResult, len(hashes))
+	sem := make(chan struct{}, opt.concurrency)
+	var wg sync.WaitGroup
+	for i, hash := range hashes {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, hash string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			tx, err := c.waitForTx(ctx, hash, targetStatus, opt)
+			results[i] = &WaitResult{Hash: hash, Tx: tx, Err: err}
+		}(i, hash)
+	}
+	wg.Wait()
+	return results
+}
+
+func (c *l2Client) waitForTx(ctx context.Context, hash string, targetStatus types.TxStatus, opt *waitOption) (*types.EnrichedTx, error) {
+	policy := &RetryPolicy{
+		InitialBackoff: opt.pollInterval,
+		MaxBackoff:     opt.maxPollInterval,
+		Multiplier:     1.5,
+		Jitter:         0.2,
+	}
+	for poll := 0; ; poll++ {
+		tx, err := c.GetTxWithContext(ctx, hash)
+		switch {
+		case err == nil:
+			status := tx.TxStatus()
+			if status.IsFailed() {
+				return tx, &TxFailedError{Tx: tx}
+			}
+			if status >= targetStatus {
+				return tx, nil
+			}
+			if status == types.TxStatusPending && tx.ExpiredAt > 0 && time.Now().UnixMilli() > tx.ExpiredAt {
+				return tx, &TxExpiredError{Tx: tx}
+			}
+		case errors.Is(err, ErrTxNotFound) || isTransientError(err, defaultRetryStatusCodes):
+			// the tx may not be indexed yet or the api is briefly unavailable, poll again
+		default:
+			return nil, err
+		}
+
+		timer := time.NewTimer(policy.backoff(poll))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// txServer answers GetTx with the next status of each tx, the last status is repeated.
+type txServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses map[string][]int64
+	polls    map[string]int
+}
+
+func newTxServer(statuses map[string][]int64, expiredAt int64) *txServer {
+	s := &txServer{statuses: statuses, polls: make(map[string]int)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		hash := r.URL.Query().Get("hash")
+		poll := s.polls[hash]
+		s.polls[hash]++
+		txStatuses := s.statuses[hash]
+		if poll >= len(txStatuses) {
+			poll = len(txStatuses) - 1
+		}
+		if poll < 0 || txStatuses[poll] < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":29404,"message":"tx not found"}`))
+			return
+		}
+		tx := &types.EnrichedTx{Tx: types.Tx{Hash: hash, Status: txStatuses[poll], ExpiredAt: expiredAt}}
+		_ = json.NewEncoder(w).Encode(tx)
+	}))
+	return s
+}
+
+var fastPolls = WaitWithPollInterval(time.Millisecond, 5*time.Millisecond)
+
+func TestWaitForTx(t *testing.T) {
+	server := newTxServer(map[string][]int64{
+		"hash": {-1, int64(types.TxStatusPending), int64(types.TxStatusExecuted), int64(types.TxStatusPacked), int64(types.TxStatusCommitted)},
+	}, time.Now().Add(time.Hour).UnixMilli())
+	defer server.Close()
+
+	c := NewZkBNBClient(server.URL)
+	tx, err := c.WaitForTx(context.Background(), "hash", types.TxStatusPacked, fastPolls)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(types.TxStatusPacked), tx.Status)
+	assert.Equal(t, 4, server.polls["hash"])
+
+	// a tx past the target status is returned at once
+	tx, err = c.WaitForTx(context.Background(), "hash", types.TxStatusExecuted, fastPolls)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(types.TxStatusCommitted), tx.Status)
+}
+
+func TestWaitForTxFailed(t *testing.T) {
+	server := newTxServer(map[string][]int64{
+		"hash": {int64(types.TxStatusPending), int64(types.TxStatusFailed)},
+	}, time.Now().Add(time.Hour).UnixMilli())
+	defer server.Close()
+
+	c := NewZkBNBClient(server.URL)
+	tx, err := c.WaitForTx(context.Background(), "hash", types.TxStatusVerified, fastPolls)
+	assert.ErrorIs(t, err, ErrTxFailed)
+	var failedErr *TxFailedError
+	assert.ErrorAs(t, err, &failedErr)
+	assert.Equal(t, "hash", failedErr.Tx.Hash)
+	assert.Equal(t, tx, failedErr.Tx)
+}
+
+func TestWaitForTxExpired(t *testing.T) {
+	server := newTxServer(map[string][]int64{
+		"hash": {int64(types.TxStatusPending)},
+	}, time.Now().Add(-time.Second).UnixMilli())
+	defer server.Close()
+
+	c := NewZkBNBClient(server.URL)
+	_, err := c.WaitForTx(context.Background(), "hash", types.TxStatusExecuted, fastPolls)
+	assert.ErrorIs(t, err, ErrTxExpired)
+	var expiredErr *TxExpiredError
+	assert.ErrorAs(t, err, &expiredErr)
+}
+
+func TestWaitForTxCanceled(t *testing.T) {
+	server := newTxServer(map[string][]int64{"hash": {-1}}, 0)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	c := NewZkBNBClient(server.URL)
+	_, err := c.WaitForTx(ctx, "hash", types.TxStatusExecuted, fastPolls)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWaitForTxs(t *testing.T) {
+	server := newTxServer(map[string][]int64{
+		"a": {int64(types.TxStatusPending), int64(types.TxStatusExecuted)},
+		"b": {int64(types.TxStatusFailed)},
+		"c": {-1, int64(types.TxStatusVerified)},
+	}, time.Now().Add(time.Hour).UnixMilli())
+	defer server.Close()
+
+	c := NewZkBNBClient(server.URL)
+	results := c.WaitForTxs(context.Background(), []string{"a", "b", "c"}, types.TxStatusExecuted, fastPolls, WaitWithConcurrency(2))
+	assert.Len(t, results, 3)
+	assert.Equal(t, "a", results[0].Hash)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, int64(types.TxStatusExecuted), results[0].Tx.Status)
+	assert.ErrorIs(t, results[1].Err, ErrTxFailed)
+	assert.NoError(t, results[2].Err)
+	assert.Equal(t, int64(types.TxStatusVerified), results[2].Tx.Status)
+}
@@ -0,0 +1,224 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+const defaultPollInterval = 3 * time.Second
+
+type watchOption struct {
+	pollInterval time.Duration
+}
+
+type WatchOptionFunc func(*watchOption)
+
+// WatchWithPollInterval sets how often ZkBNB is polled for new blocks and status changes, 3s by default
+func WatchWithPollInterval(pollInterval time.Duration) WatchOptionFunc {
+	return func(o *watchOption) {
+		o.pollInterval = pollInterval
+	}
+}
+
+type BlockEventType int
+
+const (
+	// BlockProduced is emitted once for every block, with its txs
+	BlockProduced BlockEventType = iota
+	// BlockCommitted is emitted when the block is committed on l1
+	BlockCommitted
+	// BlockVerified is emitted when the block is verified on l1
+	BlockVerified
+)
+
+func (t BlockEventType) String() string {
+	switch t {
+	case BlockProduced:
+		return "produced"
+	case BlockCommitted:
+		return "committed"
+	case BlockVerified:
+		return "verified"
+	}
+	return "unknown"
+}
+
+// BlockCheckpoint is the progress of a block watcher, every height is the next block to emit the event for.
+type BlockCheckpoint struct {
+	Height          int64 `json:"height"`
+	CommittedHeight int64 `json:"committed_height"`
+	VerifiedHeight  int64 `json:"verified_height"`
+}
+
+// BlockEvent is a change of a block. Checkpoint is the progress of the watcher once the event is handled,
+// save it to resume watching with WatchBlocksFromCheckpoint.
+type BlockEvent struct {
+	Type       BlockEventType
+	Block      *types.Block
+	Checkpoint BlockCheckpoint
+}
+
+// BlockSubscription streams the block events of a watcher.
+type BlockSubscription struct {
+	events chan BlockEvent
+	err    error
+}
+
+// Events returns the block events, the channel is closed when the watcher stops
+func (s *BlockSubscription) Events() <-chan BlockEvent {
+	return s.events
+}
+
+// Err returns the error that stopped the watcher once the events channel is closed
+func (s *BlockSubscription) Err() error {
+	return s.err
+}
+
+func (c *l2Client) WatchBlocks(ctx context.Context, fromHeight int64, options ...WatchOptionFunc) *BlockSubscription {
+	return c.WatchBlocksFromCheckpoint(ctx, BlockCheckpoint{
+		Height:          fromHeight,
+		CommittedHeight: fromHeight,
+		VerifiedHeight:  fromHeight,
+	}, options...)
+}
+
+func (c *l2Client) WatchBlocksFromCheckpoint(ctx context.Context, checkpoint BlockCheckpoint, options ...WatchOptionFunc) *BlockSubscription {
+	opt := &watchOption{pollInterval: defaultPollInterval}
+	for _, f := range options {
+		f(opt)
+	}
+
+	w := &blockWatcher{
+		c:          c,
+		checkpoint: checkpoint,
+		blocks:     make(map[int64]*types.Block),
+		sub:        &BlockSubscription{events: make(chan BlockEvent)},
+	}
+	go w.run(ctx, opt.pollInterval)
+	return w.sub
+}
+
+type blockWatcher struct {
+	c          *l2Client
+	checkpoint BlockCheckpoint
+	// blocks are the emitted blocks which are not verified yet
+	blocks map[int64]*types.Block
+	sub    *BlockSubscription
+}
+
+func (w *blockWatcher) run(ctx context.Context, pollInterval time.Duration) {
+	defer close(w.sub.events)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		// transient errors and the blocks not indexed yet by the api are retried at the next poll
+		if err := w.poll(ctx); err != nil && !errors.Is(err, ErrNotFound) && !isTransientError(err, defaultRetryStatusCodes) {
+			w.sub.err = err
+			return
+		}
+		select {
+		case <-ctx.Done():
+			w.sub.err = ctx.Err()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *blockWatcher) poll(ctx context.Context) error {
+	currentHeight, err := w.c.GetCurrentHeightWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the blocks fetched in this poll, their status is up to date
+	fresh := make(map[int64]*types.Block)
+	for w.checkpoint.Height <= currentHeight {
+		block, err := w.fetchBlock(ctx, w.checkpoint.Height)
+		if err != nil {
+			return err
+		}
+		fresh[block.Height] = block
+		w.blocks[block.Height] = block
+		w.checkpoint.Height++
+		if err := w.emit(ctx, BlockProduced, block); err != nil {
+			return err
+		}
+	}
+
+	for w.checkpoint.CommittedHeight < w.checkpoint.Height {
+		block, err := w.freshBlock(ctx, fresh, w.checkpoint.CommittedHeight)
+		if err != nil {
+			return err
+		}
+		// blocks are committed in order
+		if block.CommittedTxHash == "" && block.CommittedAt == 0 {
+			break
+		}
+		w.checkpoint.CommittedHeight++
+		if err := w.emit(ctx, BlockCommitted, block); err != nil {
+			return err
+		}
+	}
+
+	for w.checkpoint.VerifiedHeight < w.checkpoint.CommittedHeight {
+		block, err := w.freshBlock(ctx, fresh, w.checkpoint.VerifiedHeight)
+		if err != nil {
+			return err
+		}
+		if block.VerifiedTxHash == "" && block.VerifiedAt == 0 {
+			break
+		}
+		delete(w.blocks, block.Height)
+		w.checkpoint.VerifiedHeight++
+		if err := w.emit(ctx, BlockVerified, block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchBlock returns the block at height with its txs
+func (w *blockWatcher) fetchBlock(ctx context.Context, height int64) (*types.Block, error) {
+	block, err := w.c.GetBlockByHeightWithContext(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	if emitted, ok := w.blocks[height]; ok {
+		block.Txs = emitted.Txs
+		return block, nil
+	}
+	txs, err := w.c.GetTxsByBlockHeightWithContext(ctx, uint32(height))
+	if err != nil {
+		return nil, err
+	}
+	block.Txs = txs
+	return block, nil
+}
+
+// freshBlock returns the block at height fetched in this poll, fetching it if needed
+func (w *blockWatcher) freshBlock(ctx context.Context, fresh map[int64]*types.Block, height int64) (*types.Block, error) {
+	if block, ok := fresh[height]; ok {
+		return block, nil
+	}
+	block, err := w.fetchBlock(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	fresh[height] = block
+	w.blocks[height] = block
+	return block, nil
+}
+
+func (w *blockWatcher) emit(ctx context.Context, eventType BlockEventType, block *types.Block) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case w.sub.events <- BlockEvent{Type: eventType, Block: block, Checkpoint: w.checkpoint}:
+		return nil
+	}
+}
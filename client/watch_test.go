@@ -0,0 +1,197 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// chainServer serves the blocks of a chain which can be updated by the test.
+type chainServer struct {
+	*httptest.Server
+	mu     sync.Mutex
+	blocks map[int64]*types.Block
+	height int64
+	// fail makes the next requests fail with this status
+	fail int
+	// notFound is the number of the next block requests which do not find the block
+	notFound int
+}
+
+func newChainServer(t *testing.T) *chainServer {
+	s := &chainServer{blocks: make(map[int64]*types.Block)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.fail != 0 {
+			w.WriteHeader(s.fail)
+			_, _ = w.Write([]byte(`{"code":20001,"message":"invalid param"}`))
+			return
+		}
+		if s.notFound > 0 && r.URL.Path == "/api/v1/block" {
+			s.notFound--
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":29404,"message":"block not found"}`))
+			return
+		}
+		height, _ := strconv.ParseInt(r.URL.Query().Get("value"), 10, 64)
+		var body interface{}
+		switch r.URL.Path {
+		case "/api/v1/currentHeight":
+			body = &types.CurrentHeight{Height: s.height}
+		case "/api/v1/block":
+			block := *s.blocks[height]
+			block.Txs = nil
+			body = &block
+		case "/api/v1/blockTxs":
+			body = &types.Txs{Total: 1, Txs: []*types.Tx{{Hash: fmt.Sprintf("tx-%d", height), BlockHeight: height}}}
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	return s
+}
+
+func (s *chainServer) update(f func(s *chainServer)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f(s)
+}
+
+func (s *chainServer) produce(height int64) {
+	s.blocks[height] = &types.Block{Height: height}
+	s.height = height
+}
+
+func (s *chainServer) commit(height int64) {
+	s.blocks[height].CommittedTxHash = fmt.Sprintf("commit-%d", height)
+	s.blocks[height].CommittedAt = 1
+}
+
+func (s *chainServer) verify(height int64) {
+	s.blocks[height].VerifiedTxHash = fmt.Sprintf("verify-%d", height)
+	s.blocks[height].VerifiedAt = 1
+}
+
+type watchedEvent struct {
+	Type   BlockEventType
+	Height int64
+}
+
+func nextEvents(t *testing.T, sub *BlockSubscription, n int) ([]watchedEvent, BlockEvent) {
+	var events []watchedEvent
+	var last BlockEvent
+	for i := 0; i < n; i++ {
+		select {
+		case event, ok := <-sub.Events():
+			if !assert.True(t, ok, "events closed: %v", sub.Err()) {
+				return events, last
+			}
+			assert.Len(t, event.Block.Txs, 1)
+			assert.Equal(t, fmt.Sprintf("tx-%d", event.Block.Height), event.Block.Txs[0].Hash)
+			events = append(events, watchedEvent{event.Type, event.Block.Height})
+			last = event
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no event after %v", events)
+		}
+	}
+	return events, last
+}
+
+func TestWatchBlocks(t *testing.T) {
+	server := newChainServer(t)
+	defer server.Close()
+	server.update(func(s *chainServer) {
+		s.produce(1)
+		s.produce(2)
+		s.commit(1)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := NewZkBNBClient(server.URL)
+	sub := c.WatchBlocks(ctx, 1, WatchWithPollInterval(10*time.Millisecond))
+
+	events, _ := nextEvents(t, sub, 3)
+	assert.Equal(t, []watchedEvent{{BlockProduced, 1}, {BlockProduced, 2}, {BlockCommitted, 1}}, events)
+
+	server.update(func(s *chainServer) {
+		s.produce(3)
+		s.commit(2)
+		s.verify(1)
+	})
+	events, last := nextEvents(t, sub, 3)
+	assert.Equal(t, []watchedEvent{{BlockProduced, 3}, {BlockCommitted, 2}, {BlockVerified, 1}}, events)
+	assert.Equal(t, "verify-1", last.Block.VerifiedTxHash)
+	assert.Equal(t, BlockCheckpoint{Height: 4, CommittedHeight: 3, VerifiedHeight: 2}, last.Checkpoint)
+
+	cancel()
+	for range sub.Events() {
+	}
+	assert.ErrorIs(t, sub.Err(), context.Canceled)
+}
+
+func TestWatchBlocksFromCheckpoint(t *testing.T) {
+	server := newChainServer(t)
+	defer server.Close()
+	server.update(func(s *chainServer) {
+		for height := int64(1); height <= 3; height++ {
+			s.produce(height)
+		}
+		s.commit(1)
+		s.commit(2)
+		s.verify(1)
+		s.verify(2)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := NewZkBNBClient(server.URL)
+	sub := c.WatchBlocksFromCheckpoint(ctx, BlockCheckpoint{Height: 3, CommittedHeight: 2, VerifiedHeight: 1},
+		WatchWithPollInterval(10*time.Millisecond))
+
+	events, last := nextEvents(t, sub, 4)
+	assert.Equal(t, []watchedEvent{{BlockProduced, 3}, {BlockCommitted, 2}, {BlockVerified, 1}, {BlockVerified, 2}}, events)
+	assert.Equal(t, BlockCheckpoint{Height: 4, CommittedHeight: 3, VerifiedHeight: 3}, last.Checkpoint)
+}
+
+func TestWatchBlocksErrors(t *testing.T) {
+	server := newChainServer(t)
+	defer server.Close()
+	server.update(func(s *chainServer) {
+		s.produce(1)
+		s.fail = http.StatusServiceUnavailable
+	})
+
+	c := NewZkBNBClient(server.URL)
+	sub := c.WatchBlocks(context.Background(), 1, WatchWithPollInterval(10*time.Millisecond))
+	time.Sleep(50 * time.Millisecond)
+
+	// transient errors are retried at the next poll
+	server.update(func(s *chainServer) { s.fail = 0 })
+	events, _ := nextEvents(t, sub, 1)
+	assert.Equal(t, []watchedEvent{{BlockProduced, 1}}, events)
+
+	// a block not found yet is polled again
+	server.update(func(s *chainServer) {
+		s.produce(2)
+		s.notFound = 1
+	})
+	events, _ = nextEvents(t, sub, 1)
+	assert.Equal(t, []watchedEvent{{BlockProduced, 2}}, events)
+
+	server.update(func(s *chainServer) { s.fail = http.StatusBadRequest })
+	for range sub.Events() {
+	}
+	assert.ErrorIs(t, sub.Err(), ErrInvalidParam)
+}
@@ -2,7 +2,6 @@ package txutils
 
 import (
 	"encoding/json"
-	"errors"
 	"math/big"
 	"strings"
 
@@ -14,6 +13,7 @@ import (
 	"github.com/bnb-chain/zkbnb-crypto/ffmath"
 	"github.com/bnb-chain/zkbnb-go-sdk/accounts"
 	"github.com/bnb-chain/zkbnb-go-sdk/types"
+	"github.com/bnb-chain/zkbnb-go-sdk/zns"
 )
 
 func ConstructWithdrawTxInfo(key accounts.Signer, tx *types.WithdrawReq, ops *types.TransactOpts) (string, error) {
@@ -162,8 +162,12 @@ func ConstructOfferTx(key accounts.Signer, tx *types.OfferTxInfo) (string, error
 }
 
 func ConstructMintNftTx(key accounts.Signer, tx *types.MintNftTxReq, ops *types.TransactOpts) (string, error) {
+	tx, err := ResolveNftContent(tx)
+	if err != nil {
+		return "", err
+	}
 	convertedTx := ConvertMintNftTxInfo(tx, ops)
-	err := convertedTx.Validate()
+	err = convertedTx.Validate()
 	if err != nil {
 		return "", err
 	}
@@ -238,11 +242,13 @@ func keccakHash(value []byte) []byte {
 	return hashVal[:]
 }
 
+// AccountNameHash returns the name hash of the account name, the name is normalized with zns.Normalize first
 func AccountNameHash(accountName string) (res string, err error) {
-	words := strings.Split(accountName, ".")
-	if len(words) != 2 {
-		return "", errors.New("[AccountNameHash] invalid account name")
+	accountName, err = zns.Normalize(accountName)
+	if err != nil {
+		return "", err
 	}
+	words := strings.Split(accountName, ".")
 
 	q, _ := big.NewInt(0).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
 
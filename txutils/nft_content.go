@@ -0,0 +1,300 @@
+package txutils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	curve "github.com/bnb-chain/zkbnb-crypto/ecc/ztwistededwards/tebn254"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// The content codecs of the CIDs
+const (
+	// CodecRaw is the codec of raw content, like an image file added to IPFS as a single block
+	CodecRaw uint64 = 0x55
+	// CodecDagPB is the codec of the CIDv0 and of the IPFS files chunked in a merkle dag
+	CodecDagPB uint64 = 0x70
+)
+
+// multihashSha256 is the multihash code of sha2-256, the only hash function of the CIDs supported
+const multihashSha256 = 0x12
+
+var (
+	// ErrInvalidCID is returned for a string which is not a CID
+	ErrInvalidCID = errors.New("invalid cid")
+	// ErrUnsupportedCID is returned for a CID which is not hashed with sha2-256
+	ErrUnsupportedCID = errors.New("unsupported cid")
+	// ErrNoNftContent is returned for a mint nft request without content hash nor content
+	ErrNoNftContent = errors.New("nft content hash is not set")
+	// ErrConflictingNftContent is returned for a mint nft request with several sources of its content hash
+	ErrConflictingNftContent = errors.New("conflicting nft content sources")
+)
+
+var (
+	base32Lower = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+	base32Upper = base32.NewEncoding("ABCDEFGHIJKLMNOPQRSTUVWXYZ234567").WithPadding(base32.NoPadding)
+)
+
+// NftContentHashFromCID returns the content hash of the content of an IPFS CIDv0, e.g. "Qm...", or CIDv1, e.g.
+// "bafy...", which is the sha2-256 digest of the CID reduced like NftContentHash
+func NftContentHashFromCID(cid string) (string, error) {
+	digest, err := parseCID(cid)
+	if err != nil {
+		return "", err
+	}
+	return NftContentHash(hex.EncodeToString(digest)), nil
+}
+
+// NftContentHashOf returns the content hash of raw content, e.g. an image, which is its sha2-256 hash reduced like
+// NftContentHash. It is the content hash of the CID of the content returned by CIDOf as well.
+func NftContentHashOf(content []byte) string {
+	digest := sha256.Sum256(content)
+	return NftContentHash(hex.EncodeToString(digest[:]))
+}
+
+// NftContentHashOfReader is NftContentHashOf for the content read from r
+func NftContentHashOfReader(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return NftContentHash(hex.EncodeToString(h.Sum(nil))), nil
+}
+
+// NftContentHashOfFile is NftContentHashOf for the content of a file
+func NftContentHashOfFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return NftContentHashOfReader(f)
+}
+
+// NftContentHashOfMetadata returns the content hash of the metadata JSON of a nft with the JSON which is hashed. The
+// metadata is the JSON itself as []byte, json.RawMessage or string, which is hashed as given, or a value which is
+// marshaled to JSON without escaping HTML characters. Upload the returned JSON, so that its hash is the content hash.
+func NftContentHashOfMetadata(metadata interface{}) (contentHash string, content []byte, err error) {
+	content, err = metadataJSON(metadata)
+	if err != nil {
+		return "", nil, err
+	}
+	return NftContentHashOf(content), content, nil
+}
+
+func metadataJSON(metadata interface{}) ([]byte, error) {
+	var content []byte
+	switch metadata := metadata.(type) {
+	case []byte:
+		content = metadata
+	case json.RawMessage:
+		content = metadata
+	case string:
+		content = []byte(metadata)
+	default:
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(metadata); err != nil {
+			return nil, err
+		}
+		// the encoder ends the JSON with a newline
+		return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+	}
+	if !json.Valid(content) {
+		return nil, fmt.Errorf("invalid nft metadata: %q is not JSON", content)
+	}
+	return content, nil
+}
+
+// CIDOf returns the CIDv1 of raw content added to IPFS as a single block, e.g. "bafkrei..."
+func CIDOf(content []byte) string {
+	digest := sha256.Sum256(content)
+	return formatCID(1, CodecRaw, digest[:])
+}
+
+// NftContentHashToCIDs returns the CIDs of the given version and codec whose content hash is contentHash. The content
+// hash is a sha2-256 digest reduced modulo the curve, so that a few digests share a content hash: the CID the content
+// hash was derived from is one of them, the first one being the CID of the digest equal to the content hash.
+func NftContentHashToCIDs(contentHash string, version int, codec uint64) ([]string, error) {
+	if version != 0 && version != 1 {
+		return nil, fmt.Errorf("%w: cid version %d", ErrUnsupportedCID, version)
+	}
+	if version == 0 && codec != CodecDagPB {
+		return nil, fmt.Errorf("%w: a cid v0 has the dag-pb codec, not 0x%x", ErrUnsupportedCID, codec)
+	}
+	value, ok := new(big.Int).SetString(strings.TrimPrefix(contentHash, "0x"), 16)
+	if !ok || len(strings.TrimPrefix(contentHash, "0x")) != 64 || value.Cmp(curve.Modulus) >= 0 {
+		return nil, fmt.Errorf("invalid nft content hash %q", contentHash)
+	}
+
+	limit := new(big.Int).Lsh(big.NewInt(1), 256)
+	var cids []string
+	for ; value.Cmp(limit) < 0; value.Add(value, curve.Modulus) {
+		cids = append(cids, formatCID(version, codec, value.FillBytes(make([]byte, 32))))
+	}
+	return cids, nil
+}
+
+// ResolveNftContent returns the mint nft request with the content hash derived from NftCID, NftContent or
+// NftMetadata, or the request itself when NftContentHash is set. Only one of them can be set. The content sources
+// are cleared, so that the returned request is self contained.
+func ResolveNftContent(tx *types.MintNftTxReq) (*types.MintNftTxReq, error) {
+	var sources []string
+	for _, source := range []struct {
+		name string
+		set  bool
+	}{
+		{"NftContentHash", tx.NftContentHash != ""},
+		{"NftCID", tx.NftCID != ""},
+		{"NftContent", tx.NftContent != nil},
+		{"NftMetadata", tx.NftMetadata != nil},
+	} {
+		if source.set {
+			sources = append(sources, source.name)
+		}
+	}
+	switch {
+	case len(sources) == 0:
+		return nil, ErrNoNftContent
+	case len(sources) > 1:
+		return nil, fmt.Errorf("%w: %s are all set", ErrConflictingNftContent, strings.Join(sources, ", "))
+	case tx.NftContentHash != "":
+		return tx, nil
+	}
+
+	resolved := *tx
+	resolved.NftCID, resolved.NftContent, resolved.NftMetadata = "", nil, nil
+	var err error
+	switch {
+	case tx.NftCID != "":
+		resolved.NftContentHash, err = NftContentHashFromCID(tx.NftCID)
+	case tx.NftContent != nil:
+		resolved.NftContentHash = NftContentHashOf(tx.NftContent)
+	default:
+		resolved.NftContentHash, _, err = NftContentHashOfMetadata(tx.NftMetadata)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &resolved, nil
+}
+
+// parseCID returns the sha2-256 digest of a CIDv0 or of a CIDv1 in base32, base58btc or base16
+func parseCID(cid string) (digest []byte, err error) {
+	if len(cid) == 46 && strings.HasPrefix(cid, "Qm") {
+		multihash, err := decodeBase58(cid)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q, %v", ErrInvalidCID, cid, err)
+		}
+		return parseMultihash(multihash, cid)
+	}
+	if len(cid) < 2 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidCID, cid)
+	}
+
+	var data []byte
+	switch cid[0] {
+	case 'b':
+		data, err = base32Lower.DecodeString(cid[1:])
+	case 'B':
+		data, err = base32Upper.DecodeString(cid[1:])
+	case 'z':
+		data, err = decodeBase58(cid[1:])
+	case 'f', 'F':
+		data, err = hex.DecodeString(cid[1:])
+	default:
+		return nil, fmt.Errorf("%w: %q has an unsupported multibase prefix %q", ErrUnsupportedCID, cid, cid[0])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q, %v", ErrInvalidCID, cid, err)
+	}
+
+	version, n := binary.Uvarint(data)
+	if n <= 0 || version != 1 {
+		return nil, fmt.Errorf("%w: %q is not a cid v1", ErrInvalidCID, cid)
+	}
+	data = data[n:]
+	if _, n = binary.Uvarint(data); n <= 0 {
+		return nil, fmt.Errorf("%w: %q has no codec", ErrInvalidCID, cid)
+	}
+	return parseMultihash(data[n:], cid)
+}
+
+func parseMultihash(multihash []byte, cid string) ([]byte, error) {
+	code, n := binary.Uvarint(multihash)
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: %q has no multihash", ErrInvalidCID, cid)
+	}
+	if code != multihashSha256 {
+		return nil, fmt.Errorf("%w: %q is not hashed with sha2-256", ErrUnsupportedCID, cid)
+	}
+	multihash = multihash[n:]
+	length, n := binary.Uvarint(multihash)
+	if n <= 0 || length != sha256.Size || len(multihash[n:]) != sha256.Size {
+		return nil, fmt.Errorf("%w: %q has an invalid digest length", ErrInvalidCID, cid)
+	}
+	return multihash[n:], nil
+}
+
+func formatCID(version int, codec uint64, digest []byte) string {
+	multihash := append([]byte{multihashSha256, sha256.Size}, digest...)
+	if version == 0 {
+		return encodeBase58(multihash)
+	}
+	data := make([]byte, 1+binary.MaxVarintLen64)
+	data[0] = 1
+	n := binary.PutUvarint(data[1:], codec)
+	return "b" + base32Lower.EncodeToString(append(data[:1+n], multihash...))
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var base58Radix = big.NewInt(58)
+
+func encodeBase58(data []byte) string {
+	var result []byte
+	value := new(big.Int).SetBytes(data)
+	mod := new(big.Int)
+	for value.Sign() > 0 {
+		value.QuoRem(value, base58Radix, mod)
+		result = append(result, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		result = append(result, base58Alphabet[0])
+	}
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return string(result)
+}
+
+func decodeBase58(s string) ([]byte, error) {
+	value := new(big.Int)
+	zeros := 0
+	for i := 0; i < len(s) && s[i] == base58Alphabet[0]; i++ {
+		zeros++
+	}
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(base58Alphabet, s[i])
+		if digit < 0 {
+			return nil, fmt.Errorf("illegal base58 character %q", s[i])
+		}
+		value.Mul(value, base58Radix)
+		value.Add(value, big.NewInt(int64(digit)))
+	}
+	return append(make([]byte, zeros), value.Bytes()...), nil
+}
@@ -0,0 +1,147 @@
+package txutils
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+const cidV0 = "QmY7Yh4UquoXHLPFo2XbhXkhBvFoPwmQUSa92pxnxjQuPU"
+
+func TestNftContentHashFromCID(t *testing.T) {
+	expected, err := NftContentHashFromCID(cidV0)
+	require.NoError(t, err)
+	assert.Len(t, expected, 64)
+
+	digest, err := parseCID(cidV0)
+	require.NoError(t, err)
+	cidV1 := formatCID(1, CodecDagPB, digest)
+	data, err := base32Lower.DecodeString(cidV1[1:])
+	require.NoError(t, err)
+	for _, cid := range []string{
+		cidV1,
+		"B" + strings.ToUpper(cidV1[1:]),
+		"z" + encodeBase58(data),
+		"f" + hex.EncodeToString(data),
+	} {
+		contentHash, err := NftContentHashFromCID(cid)
+		if assert.NoError(t, err, cid) {
+			assert.Equal(t, expected, contentHash, cid)
+		}
+	}
+
+	// the sha2-256 of no content, which is its CIDv1 as a raw block
+	emptyCID := "bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku"
+	assert.Equal(t, emptyCID, CIDOf(nil))
+	contentHash, err := NftContentHashFromCID(emptyCID)
+	require.NoError(t, err)
+	assert.Equal(t, NftContentHashOf(nil), contentHash)
+
+	for cid, expected := range map[string]error{
+		"":                   ErrInvalidCID,
+		"Qm" + cidV0[2:45]:   ErrUnsupportedCID,
+		"Qm0" + cidV0[3:]:    ErrInvalidCID,
+		"bafy!":              ErrInvalidCID,
+		"m" + cidV1[1:]:      ErrUnsupportedCID,
+		"f0155":              ErrInvalidCID,
+		"f01551220" + "abcd": ErrInvalidCID,
+		// a blake2b-256 multihash
+		"f0155a0e40220" + strings.Repeat("00", 32): ErrUnsupportedCID,
+	} {
+		_, err := NftContentHashFromCID(cid)
+		assert.ErrorIs(t, err, expected, cid)
+	}
+}
+
+func TestNftContentHashToCIDs(t *testing.T) {
+	contentHash, err := NftContentHashFromCID(cidV0)
+	require.NoError(t, err)
+	cids, err := NftContentHashToCIDs(contentHash, 0, CodecDagPB)
+	require.NoError(t, err)
+	assert.Contains(t, cids, cidV0)
+	for _, cid := range cids {
+		h, err := NftContentHashFromCID(cid)
+		require.NoError(t, err)
+		assert.Equal(t, contentHash, h)
+	}
+
+	content := []byte("nft")
+	cids, err = NftContentHashToCIDs(NftContentHashOf(content), 1, CodecRaw)
+	require.NoError(t, err)
+	assert.Contains(t, cids, CIDOf(content))
+
+	_, err = NftContentHashToCIDs(contentHash, 0, CodecRaw)
+	assert.ErrorIs(t, err, ErrUnsupportedCID)
+	_, err = NftContentHashToCIDs(contentHash, 2, CodecRaw)
+	assert.ErrorIs(t, err, ErrUnsupportedCID)
+	_, err = NftContentHashToCIDs("abcd", 1, CodecRaw)
+	assert.EqualError(t, err, `invalid nft content hash "abcd"`)
+}
+
+func TestNftContentHashOf(t *testing.T) {
+	content := []byte("an image")
+	path := filepath.Join(t.TempDir(), "image.png")
+	require.NoError(t, os.WriteFile(path, content, 0o600))
+	contentHash, err := NftContentHashOfFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, NftContentHashOf(content), contentHash)
+	_, err = NftContentHashOfFile(filepath.Join(t.TempDir(), "missing.png"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	// the JSON is hashed as given
+	raw := "{\n  \"attributes\": [1, 2],\n  \"name\": \"nft\"\n}"
+	for _, metadata := range []interface{}{raw, []byte(raw), json.RawMessage(raw)} {
+		contentHash, content, err := NftContentHashOfMetadata(metadata)
+		if assert.NoError(t, err) {
+			assert.Equal(t, raw, string(content))
+			assert.Equal(t, NftContentHashOf([]byte(raw)), contentHash)
+		}
+	}
+	contentHash, content, err = NftContentHashOfMetadata(struct {
+		Name  string `json:"name"`
+		Image string `json:"image"`
+	}{"<nft> & co", "ipfs://" + cidV0})
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"<nft> & co","image":"ipfs://`+cidV0+`"}`, string(content))
+	assert.Equal(t, NftContentHashOf(content), contentHash)
+	_, _, err = NftContentHashOfMetadata(`{"name":`)
+	assert.Error(t, err)
+}
+
+func TestResolveNftContent(t *testing.T) {
+	contentHash, err := NftContentHashFromCID(cidV0)
+	require.NoError(t, err)
+
+	tx, err := ResolveNftContent(&types.MintNftTxReq{To: "gavin.legend", NftCID: cidV0})
+	require.NoError(t, err)
+	assert.Equal(t, &types.MintNftTxReq{To: "gavin.legend", NftContentHash: contentHash}, tx)
+
+	tx, err = ResolveNftContent(&types.MintNftTxReq{NftContent: []byte("nft")})
+	require.NoError(t, err)
+	assert.Equal(t, NftContentHashOf([]byte("nft")), tx.NftContentHash)
+
+	tx, err = ResolveNftContent(&types.MintNftTxReq{NftMetadata: `{"name":"nft"}`})
+	require.NoError(t, err)
+	assert.Equal(t, NftContentHashOf([]byte(`{"name":"nft"}`)), tx.NftContentHash)
+
+	tx, err = ResolveNftContent(&types.MintNftTxReq{NftContentHash: "hash"})
+	require.NoError(t, err)
+	assert.Equal(t, &types.MintNftTxReq{NftContentHash: "hash"}, tx)
+
+	_, err = ResolveNftContent(&types.MintNftTxReq{NftContentHash: "hash", NftCID: cidV0})
+	assert.ErrorIs(t, err, ErrConflictingNftContent)
+	_, err = ResolveNftContent(&types.MintNftTxReq{NftCID: cidV0, NftContent: []byte("nft")})
+	assert.EqualError(t, err, "conflicting nft content sources: NftCID, NftContent are all set")
+	_, err = ResolveNftContent(&types.MintNftTxReq{To: "gavin.legend"})
+	assert.ErrorIs(t, err, ErrNoNftContent)
+	_, err = ResolveNftContent(&types.MintNftTxReq{NftCID: "bafy!"})
+	assert.ErrorIs(t, err, ErrInvalidCID)
+}
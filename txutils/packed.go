@@ -0,0 +1,167 @@
+package txutils
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/zkbnb-crypto/util"
+)
+
+var (
+	// ErrAmountNotPackable is returned for an amount which the packed amount format of ZkBNB can not encode exactly
+	ErrAmountNotPackable = errors.New("amount is not packable")
+	// ErrFeeNotPackable is returned for a gas fee which the packed fee format of ZkBNB can not encode exactly
+	ErrFeeNotPackable = errors.New("gas fee is not packable")
+	// ErrPackedOutOfRange is returned for a negative amount or an amount above the max of its packed format
+	ErrPackedOutOfRange = errors.New("amount is out of the packed range")
+)
+
+// packedFormat is a packed floating format of ZkBNB, a value is a mantissa of limited bits times a power of ten
+type packedFormat struct {
+	name        string
+	maxMantissa *big.Int
+	max         *big.Int
+	err         error
+}
+
+var (
+	// packedAmount keeps 35 bits of mantissa, it encodes the amounts of transfers
+	packedAmount = &packedFormat{
+		name:        "amount",
+		maxMantissa: util.PackedAmountMaxMantissa,
+		max:         util.PackedAmountMaxAmount,
+		err:         ErrAmountNotPackable,
+	}
+	// packedFee keeps 11 bits of mantissa, it encodes the gas fees
+	packedFee = &packedFormat{
+		name:        "gas fee",
+		maxMantissa: util.PackedFeeMaxMantissa,
+		max:         util.PackedFeeMaxAmount,
+		err:         ErrFeeNotPackable,
+	}
+)
+
+var ten = big.NewInt(10)
+
+// scale returns the smallest power of ten by which the value has a mantissa in range
+func (f *packedFormat) scale(value *big.Int) *big.Int {
+	scale := big.NewInt(1)
+	mantissa := new(big.Int).Set(value)
+	for mantissa.Cmp(f.maxMantissa) > 0 {
+		mantissa.Quo(mantissa, ten)
+		scale.Mul(scale, ten)
+	}
+	return scale
+}
+
+func (f *packedFormat) checkRange(value *big.Int) error {
+	if value == nil || value.Sign() < 0 || value.Cmp(f.max) > 0 {
+		return fmt.Errorf("%w: %s %v, the max is %s", ErrPackedOutOfRange, f.name, value, f.max)
+	}
+	return nil
+}
+
+func (f *packedFormat) isPackable(value *big.Int) bool {
+	if f.checkRange(value) != nil {
+		return false
+	}
+	return new(big.Int).Rem(value, f.scale(value)).Sign() == 0
+}
+
+func (f *packedFormat) roundDown(value *big.Int) (*big.Int, error) {
+	if err := f.checkRange(value); err != nil {
+		return nil, err
+	}
+	scale := f.scale(value)
+	return new(big.Int).Mul(new(big.Int).Quo(value, scale), scale), nil
+}
+
+func (f *packedFormat) roundUp(value *big.Int) (*big.Int, error) {
+	if err := f.checkRange(value); err != nil {
+		return nil, err
+	}
+	scale := f.scale(value)
+	mantissa, rem := new(big.Int).QuoRem(value, scale, new(big.Int))
+	if rem.Sign() == 0 {
+		return new(big.Int).Set(value), nil
+	}
+	mantissa.Add(mantissa, big.NewInt(1))
+	if mantissa.Cmp(f.maxMantissa) > 0 {
+		// the mantissa overflows, the next packable value has a larger power of ten
+		mantissa.Quo(mantissa, ten)
+		scale.Mul(scale, ten)
+		mantissa.Add(mantissa, big.NewInt(1))
+	}
+	up := mantissa.Mul(mantissa, scale)
+	if err := f.checkRange(up); err != nil {
+		return nil, err
+	}
+	return up, nil
+}
+
+func (f *packedFormat) check(value *big.Int) error {
+	if err := f.checkRange(value); err != nil {
+		return err
+	}
+	if f.isPackable(value) {
+		return nil
+	}
+	lower, _ := f.roundDown(value)
+	upper, err := f.roundUp(value)
+	if err != nil {
+		// the value is below the max but the next packable value is above it
+		return fmt.Errorf("%w: %s, use %s instead", f.err, value, lower)
+	}
+	return fmt.Errorf("%w: %s, use %s or %s instead", f.err, value, lower, upper)
+}
+
+// IsPackableAmount tells whether the amount is exactly encoded by the packed amount format of ZkBNB, which is a
+// mantissa of 35 bits times a power of ten
+func IsPackableAmount(amount *big.Int) bool {
+	return packedAmount.isPackable(amount)
+}
+
+// IsPackableFee tells whether the gas fee is exactly encoded by the packed fee format of ZkBNB, which is a
+// mantissa of 11 bits times a power of ten
+func IsPackableFee(fee *big.Int) bool {
+	return packedFee.isPackable(fee)
+}
+
+// RoundDownAmount returns the largest packable amount not above the amount
+func RoundDownAmount(amount *big.Int) (*big.Int, error) {
+	return packedAmount.roundDown(amount)
+}
+
+// RoundUpAmount returns the smallest packable amount not below the amount, or ErrPackedOutOfRange if it is above
+// the max amount
+func RoundUpAmount(amount *big.Int) (*big.Int, error) {
+	return packedAmount.roundUp(amount)
+}
+
+// RoundDownFee returns the largest packable gas fee not above the fee
+func RoundDownFee(fee *big.Int) (*big.Int, error) {
+	return packedFee.roundDown(fee)
+}
+
+// RoundUpFee returns the smallest packable gas fee not below the fee, or ErrPackedOutOfRange if it is above the
+// max fee
+func RoundUpFee(fee *big.Int) (*big.Int, error) {
+	return packedFee.roundUp(fee)
+}
+
+// CheckPackableAmount returns ErrAmountNotPackable with the nearest packable amounts if the amount is not packable
+func CheckPackableAmount(amount *big.Int) error {
+	return packedAmount.check(amount)
+}
+
+// CheckAmountRange returns ErrPackedOutOfRange for a negative amount or an amount above the max amount of ZkBNB, the
+// amounts which are not packed, like the amounts of withdrawals, only need to be in range
+func CheckAmountRange(amount *big.Int) error {
+	return packedAmount.checkRange(amount)
+}
+
+// CheckPackableFee returns ErrFeeNotPackable with the nearest packable fees if the gas fee is not packable
+func CheckPackableFee(fee *big.Int) error {
+	return packedFee.check(fee)
+}
@@ -0,0 +1,100 @@
+package txutils
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bigInt(t *testing.T, s string) *big.Int {
+	value, ok := new(big.Int).SetString(s, 10)
+	require.True(t, ok, s)
+	return value
+}
+
+func TestPackableAmount(t *testing.T) {
+	for _, s := range []string{"0", "1", "34359738367", "34359738370", "1000000000000000000", "343597383670000000000000000000000000000000"} {
+		assert.True(t, IsPackableAmount(bigInt(t, s)), s)
+		assert.NoError(t, CheckPackableAmount(bigInt(t, s)), s)
+	}
+	for _, s := range []string{"34359738368", "1234567890123456789", "343597383670000000000000000000000000000001"} {
+		assert.False(t, IsPackableAmount(bigInt(t, s)), s)
+	}
+	assert.False(t, IsPackableAmount(big.NewInt(-1)))
+	assert.False(t, IsPackableAmount(nil))
+
+	for value, expected := range map[string][2]string{
+		"100":                 {"100", "100"},
+		"34359738368":         {"34359738360", "34359738370"},
+		"1234567890123456789": {"1234567890100000000", "1234567890200000000"},
+		// the mantissa overflows when rounding up
+		"343597383671": {"343597383670", "343597383700"},
+	} {
+		down, err := RoundDownAmount(bigInt(t, value))
+		require.NoError(t, err)
+		assert.Equal(t, expected[0], down.String(), value)
+		up, err := RoundUpAmount(bigInt(t, value))
+		require.NoError(t, err)
+		assert.Equal(t, expected[1], up.String(), value)
+		assert.True(t, IsPackableAmount(down) && IsPackableAmount(up), value)
+	}
+
+	err := CheckPackableAmount(bigInt(t, "1234567890123456789"))
+	assert.ErrorIs(t, err, ErrAmountNotPackable)
+	assert.EqualError(t, err, "amount is not packable: 1234567890123456789, use 1234567890100000000 or 1234567890200000000 instead")
+	_, err = RoundDownAmount(big.NewInt(-1))
+	assert.ErrorIs(t, err, ErrPackedOutOfRange)
+
+	max := bigInt(t, "343597383670000000000000000000000000000000")
+	assert.NoError(t, CheckPackableAmount(max))
+	up, err := RoundUpAmount(new(big.Int).Sub(max, big.NewInt(1)))
+	require.NoError(t, err)
+	assert.Equal(t, max, up)
+	assert.EqualError(t, CheckPackableAmount(new(big.Int).Sub(max, big.NewInt(1))),
+		"amount is not packable: 343597383669999999999999999999999999999999, use 343597383660000000000000000000000000000000 or 343597383670000000000000000000000000000000 instead")
+	_, err = RoundUpAmount(new(big.Int).Add(max, big.NewInt(1)))
+	assert.ErrorIs(t, err, ErrPackedOutOfRange)
+}
+
+func TestPackableFee(t *testing.T) {
+	for _, s := range []string{"0", "2047", "2050", "100000000000000"} {
+		assert.True(t, IsPackableFee(bigInt(t, s)), s)
+	}
+	for _, s := range []string{"2048", "123456"} {
+		assert.False(t, IsPackableFee(bigInt(t, s)), s)
+	}
+
+	for value, expected := range map[string][2]string{
+		"2048":   {"2040", "2050"},
+		"123456": {"123400", "123500"},
+		"20471":  {"20470", "20500"},
+	} {
+		down, err := RoundDownFee(bigInt(t, value))
+		require.NoError(t, err)
+		assert.Equal(t, expected[0], down.String(), value)
+		up, err := RoundUpFee(bigInt(t, value))
+		require.NoError(t, err)
+		assert.Equal(t, expected[1], up.String(), value)
+	}
+
+	max := bigInt(t, "20470000000000000000000000000000000")
+	up, err := RoundUpFee(new(big.Int).Sub(max, big.NewInt(1)))
+	require.NoError(t, err)
+	assert.Equal(t, max, up)
+	err = CheckPackableFee(new(big.Int).Sub(max, big.NewInt(1)))
+	assert.ErrorIs(t, err, ErrFeeNotPackable)
+	assert.EqualError(t, err, "gas fee is not packable: 20469999999999999999999999999999999, use 20460000000000000000000000000000000 or 20470000000000000000000000000000000 instead")
+	assert.NoError(t, CheckPackableFee(max))
+	_, err = RoundUpFee(new(big.Int).Add(max, big.NewInt(1)))
+	assert.ErrorIs(t, err, ErrPackedOutOfRange)
+}
+
+func TestPackedRoundUpAboveMax(t *testing.T) {
+	// a format whose max is not packable, the next packable value of the values near the max is above it
+	f := &packedFormat{name: "test", maxMantissa: big.NewInt(2047), max: big.NewInt(20455), err: ErrFeeNotPackable}
+	_, err := f.roundUp(big.NewInt(20451))
+	assert.ErrorIs(t, err, ErrPackedOutOfRange)
+	assert.EqualError(t, f.check(big.NewInt(20451)), "gas fee is not packable: 20451, use 20450 instead")
+}
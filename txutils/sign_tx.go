@@ -0,0 +1,55 @@
+package txutils
+
+import (
+	"fmt"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/accounts"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// ConstructTx signs the tx request with the ops, req is one of the requests of types.ReqTxType
+func ConstructTx(key accounts.Signer, req interface{}, ops *types.TransactOpts) (string, error) {
+	switch req := req.(type) {
+	case *types.TransferTxReq:
+		return ConstructTransferTx(key, ops, req)
+	case *types.WithdrawReq:
+		return ConstructWithdrawTxInfo(key, req, ops)
+	case *types.CreateCollectionReq:
+		return ConstructCreateCollectionTx(key, req, ops)
+	case *types.MintNftTxReq:
+		return ConstructMintNftTx(key, req, ops)
+	case *types.TransferNftTxReq:
+		return ConstructTransferNftTx(key, req, ops)
+	case *types.AtomicMatchTxReq:
+		return ConstructAtomicMatchTx(key, req, ops)
+	case *types.CancelOfferReq:
+		return ConstructCancelOfferTx(key, req, ops)
+	case *types.WithdrawNftTxReq:
+		return ConstructWithdrawNftTx(key, req, ops)
+	}
+	return "", fmt.Errorf("unsupported tx request %T", req)
+}
+
+// SignTx signs the unsigned tx, the signed tx can be sent later with SendRawTx. It needs no access to ZkBNB,
+// so that the tx can be signed on an offline machine.
+func SignTx(key accounts.Signer, tx *types.UnsignedTx) (*types.SignedTx, error) {
+	if tx.Ops == nil {
+		return nil, fmt.Errorf("the ops of the unsigned tx are not set")
+	}
+	txType, err := types.ReqTxType(tx.Req)
+	if err != nil {
+		return nil, err
+	}
+	if txType != tx.TxType {
+		return nil, fmt.Errorf("the request of the unsigned tx is of tx type %d, not %d", txType, tx.TxType)
+	}
+	txInfo, err := ConstructTx(key, tx.Req, tx.Ops)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := TxHash(txType, txInfo)
+	if err != nil {
+		return nil, err
+	}
+	return &types.SignedTx{TxType: txType, TxInfo: txInfo, Hash: hash, Ops: tx.Ops}, nil
+}
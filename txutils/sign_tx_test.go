@@ -0,0 +1,56 @@
+package txutils
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+func TestSignTx(t *testing.T) {
+	keyManager, pubKey := testKeyManager(t, "28e1a3762ff9944e9a4ad79477b756ef0aff3d2af76f0f40a0c3ec6ca76cf24b")
+	unsigned := &types.UnsignedTx{
+		Version: types.UnsignedTxVersion,
+		TxType:  types.TxTypeTransfer,
+		Req:     &types.TransferTxReq{ToAccountName: "gavin.legend", AssetAmount: big.NewInt(100)},
+		Ops:     testOps(2),
+	}
+	unsigned.Ops.TxType = types.TxTypeTransfer
+
+	// the unsigned tx is carried to the offline machine as json
+	data, err := types.MarshalUnsignedTx(unsigned)
+	require.NoError(t, err)
+	parsed, err := types.ParseUnsignedTx(data)
+	require.NoError(t, err)
+	assert.Equal(t, unsigned, parsed)
+
+	signed, err := SignTx(keyManager, parsed)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(types.TxTypeTransfer), signed.TxType)
+	assert.Equal(t, unsigned.Ops, signed.Ops)
+	assert.NoError(t, VerifyTxSignature(signed.TxType, signed.TxInfo, pubKey))
+	hash, err := TxHash(signed.TxType, signed.TxInfo)
+	require.NoError(t, err)
+	assert.Equal(t, hash, signed.Hash)
+
+	// the same tx is signed the same way online
+	txInfo, err := ConstructTx(keyManager, unsigned.Req, unsigned.Ops)
+	require.NoError(t, err)
+	assert.Equal(t, txInfo, signed.TxInfo)
+}
+
+func TestSignTxErrors(t *testing.T) {
+	keyManager, _ := testKeyManager(t, "28e1a3762ff9944e9a4ad79477b756ef0aff3d2af76f0f40a0c3ec6ca76cf24b")
+
+	_, err := SignTx(keyManager, &types.UnsignedTx{TxType: types.TxTypeWithdraw, Req: &types.TransferTxReq{}, Ops: testOps(2)})
+	assert.EqualError(t, err, "the request of the unsigned tx is of tx type 4, not 5")
+
+	_, err = SignTx(keyManager, &types.UnsignedTx{TxType: types.TxTypeTransfer, Req: &types.TransferTxReq{}})
+	assert.EqualError(t, err, "the ops of the unsigned tx are not set")
+
+	_, err = ConstructTx(keyManager, &types.OfferReq{}, testOps(2))
+	assert.EqualError(t, err, "unsupported tx request *types.OfferReq")
+}
@@ -0,0 +1,54 @@
+package txutils
+
+import (
+	"fmt"
+
+	"github.com/bnb-chain/zkbnb-crypto/wasm/txtypes"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// TxHash computes the hash of a signed l2 tx locally, it is the same as the tx hash assigned by ZkBNB
+// when the tx is sent.
+func TxHash(txType uint32, txInfo string) (string, error) {
+	tx, err := ParseTxInfo(txType, txInfo)
+	if err != nil {
+		return "", err
+	}
+
+	msgHash, err := tx.Hash(mimc.NewMiMC())
+	if err != nil {
+		return "", err
+	}
+	return common.Bytes2Hex(msgHash), nil
+}
+
+// ParseTxInfo parses the tx info of a l2 tx which can be sent by SendRawTx.
+func ParseTxInfo(txType uint32, txInfo string) (tx txtypes.TxInfo, err error) {
+	switch txType {
+	case types.TxTypeTransfer:
+		tx, err = types.ParseTransferTxInfo(txInfo)
+	case types.TxTypeWithdraw:
+		tx, err = types.ParseWithdrawTxInfo(txInfo)
+	case types.TxTypeCreateCollection:
+		tx, err = types.ParseCreateCollectionTxInfo(txInfo)
+	case types.TxTypeMintNft:
+		tx, err = types.ParseMintNftTxInfo(txInfo)
+	case types.TxTypeTransferNft:
+		tx, err = types.ParseTransferNftTxInfo(txInfo)
+	case types.TxTypeAtomicMatch:
+		tx, err = types.ParseAtomicMatchTxInfo(txInfo)
+	case types.TxTypeCancelOffer:
+		tx, err = types.ParseCancelOfferTxInfo(txInfo)
+	case types.TxTypeWithdrawNft:
+		tx, err = types.ParseWithdrawNftTxInfo(txInfo)
+	default:
+		return nil, fmt.Errorf("tx type %d is not a l2 tx", txType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
@@ -14,6 +14,9 @@ import (
 
 type PublicKey = eddsa.PublicKey
 
+// ErrInvalidSignature is returned when a tx is not signed by the expected key
+var ErrInvalidSignature = errors.New("invalid signature")
+
 func parsePk(pkStr string) (pk *PublicKey, err error) {
 	pkBytes, err := hex.DecodeString(pkStr)
 	if err != nil {
@@ -31,14 +34,15 @@ func parsePk(pkStr string) (pk *PublicKey, err error) {
 }
 
 func ConvertTransferNftTxInfo(tx *types.TransferNftTxReq, ops *types.TransactOpts) *txtypes.TransferNftTxInfo {
+	gasFeeAssetId, gasFeeAssetAmount := ops.GasFeeAssetAndAmount()
 	return &txtypes.TransferNftTxInfo{
 		FromAccountIndex:  ops.FromAccountIndex,
 		ToAccountIndex:    ops.ToAccountIndex,
 		ToAccountNameHash: ops.ToAccountNameHash,
 		NftIndex:          tx.NftIndex,
 		GasAccountIndex:   ops.GasAccountIndex,
-		GasFeeAssetId:     ops.GasFeeAssetId,
-		GasFeeAssetAmount: ops.GasFeeAssetAmount,
+		GasFeeAssetId:     gasFeeAssetId,
+		GasFeeAssetAmount: gasFeeAssetAmount,
 		CallData:          ops.CallData,
 		CallDataHash:      ops.CallDataHash,
 		ExpiredAt:         ops.ExpiredAt,
@@ -47,13 +51,14 @@ func ConvertTransferNftTxInfo(tx *types.TransferNftTxReq, ops *types.TransactOpt
 }
 
 func ConvertWithdrawNftTxInfo(tx *types.WithdrawNftTxReq, ops *types.TransactOpts) *txtypes.WithdrawNftTxInfo {
+	gasFeeAssetId, gasFeeAssetAmount := ops.GasFeeAssetAndAmount()
 	return &txtypes.WithdrawNftTxInfo{
 		AccountIndex:      tx.AccountIndex,
 		NftIndex:          tx.NftIndex,
 		ToAddress:         tx.ToAddress,
 		GasAccountIndex:   ops.GasAccountIndex,
-		GasFeeAssetId:     ops.GasFeeAssetId,
-		GasFeeAssetAmount: ops.GasFeeAssetAmount,
+		GasFeeAssetId:     gasFeeAssetId,
+		GasFeeAssetAmount: gasFeeAssetAmount,
 		ExpiredAt:         ops.ExpiredAt,
 		Nonce:             ops.Nonce,
 	}
@@ -75,6 +80,7 @@ func ConvertOfferTxInfo(tx *types.OfferTxInfo) *txtypes.OfferTxInfo {
 }
 
 func ConvertMintNftTxInfo(tx *types.MintNftTxReq, ops *types.TransactOpts) *txtypes.MintNftTxInfo {
+	gasFeeAssetId, gasFeeAssetAmount := ops.GasFeeAssetAndAmount()
 	return &txtypes.MintNftTxInfo{
 		CreatorAccountIndex: ops.FromAccountIndex,
 		ToAccountIndex:      ops.ToAccountIndex,
@@ -83,23 +89,25 @@ func ConvertMintNftTxInfo(tx *types.MintNftTxReq, ops *types.TransactOpts) *txty
 		NftCollectionId:     tx.NftCollectionId,
 		CreatorTreasuryRate: tx.CreatorTreasuryRate,
 		GasAccountIndex:     ops.GasAccountIndex,
-		GasFeeAssetId:       ops.GasFeeAssetId,
-		GasFeeAssetAmount:   ops.GasFeeAssetAmount,
+		GasFeeAssetId:       gasFeeAssetId,
+		GasFeeAssetAmount:   gasFeeAssetAmount,
 		ExpiredAt:           ops.ExpiredAt,
 		Nonce:               ops.Nonce,
 	}
 }
 
 func ConvertTransferTx(tx *types.TransferTxReq, ops *types.TransactOpts) *txtypes.TransferTxInfo {
+	assetId, assetAmount := tx.AssetAndAmount()
+	gasFeeAssetId, gasFeeAssetAmount := ops.GasFeeAssetAndAmount()
 	return &txtypes.TransferTxInfo{
 		FromAccountIndex:  ops.FromAccountIndex,
 		ToAccountIndex:    ops.ToAccountIndex,
 		ToAccountNameHash: ops.ToAccountNameHash,
-		AssetId:           tx.AssetId,
-		AssetAmount:       tx.AssetAmount,
+		AssetId:           assetId,
+		AssetAmount:       assetAmount,
 		GasAccountIndex:   ops.GasAccountIndex,
-		GasFeeAssetId:     ops.GasFeeAssetId,
-		GasFeeAssetAmount: ops.GasFeeAssetAmount,
+		GasFeeAssetId:     gasFeeAssetId,
+		GasFeeAssetAmount: gasFeeAssetAmount,
 		Memo:              ops.Memo,
 		CallData:          ops.CallData,
 		CallDataHash:      ops.CallDataHash,
@@ -109,13 +117,15 @@ func ConvertTransferTx(tx *types.TransferTxReq, ops *types.TransactOpts) *txtype
 }
 
 func ConvertWithdrawTx(tx *types.WithdrawReq, ops *types.TransactOpts) *txtypes.WithdrawTxInfo {
+	assetId, assetAmount := tx.AssetAndAmount()
+	gasFeeAssetId, gasFeeAssetAmount := ops.GasFeeAssetAndAmount()
 	return &txtypes.WithdrawTxInfo{
 		FromAccountIndex:  ops.FromAccountIndex,
-		AssetId:           tx.AssetId,
-		AssetAmount:       tx.AssetAmount,
+		AssetId:           assetId,
+		AssetAmount:       assetAmount,
 		GasAccountIndex:   ops.GasAccountIndex,
-		GasFeeAssetId:     ops.GasFeeAssetId,
-		GasFeeAssetAmount: ops.GasFeeAssetAmount,
+		GasFeeAssetId:     gasFeeAssetId,
+		GasFeeAssetAmount: gasFeeAssetAmount,
 		ToAddress:         tx.ToAddress,
 		ExpiredAt:         ops.ExpiredAt,
 		Nonce:             ops.Nonce,
@@ -123,19 +133,21 @@ func ConvertWithdrawTx(tx *types.WithdrawReq, ops *types.TransactOpts) *txtypes.
 }
 
 func ConvertCreateCollectionTxInfo(tx *types.CreateCollectionReq, ops *types.TransactOpts) *txtypes.CreateCollectionTxInfo {
+	gasFeeAssetId, gasFeeAssetAmount := ops.GasFeeAssetAndAmount()
 	return &txtypes.CreateCollectionTxInfo{
 		AccountIndex:      ops.FromAccountIndex,
 		Name:              tx.Name,
 		Introduction:      tx.Introduction,
 		GasAccountIndex:   ops.GasAccountIndex,
-		GasFeeAssetId:     ops.GasFeeAssetId,
-		GasFeeAssetAmount: ops.GasFeeAssetAmount,
+		GasFeeAssetId:     gasFeeAssetId,
+		GasFeeAssetAmount: gasFeeAssetAmount,
 		ExpiredAt:         ops.ExpiredAt,
 		Nonce:             ops.Nonce,
 	}
 }
 
 func ConvertAtomicMatchTxInfo(tx *types.AtomicMatchTxReq, ops *types.TransactOpts) *txtypes.AtomicMatchTxInfo {
+	gasFeeAssetId, gasFeeAssetAmount := ops.GasFeeAssetAndAmount()
 	return &txtypes.AtomicMatchTxInfo{
 		AccountIndex: ops.FromAccountIndex,
 		BuyOffer: &txtypes.OfferTxInfo{
@@ -163,20 +175,21 @@ func ConvertAtomicMatchTxInfo(tx *types.AtomicMatchTxReq, ops *types.TransactOpt
 			Sig:          tx.SellOffer.Sig,
 		},
 		GasAccountIndex:   ops.GasAccountIndex,
-		GasFeeAssetId:     ops.GasFeeAssetId,
-		GasFeeAssetAmount: ops.GasFeeAssetAmount,
+		GasFeeAssetId:     gasFeeAssetId,
+		GasFeeAssetAmount: gasFeeAssetAmount,
 		Nonce:             ops.Nonce,
 		ExpiredAt:         ops.ExpiredAt,
 	}
 }
 
 func ConvertCancelOfferTxInfo(tx *types.CancelOfferReq, ops *types.TransactOpts) *txtypes.CancelOfferTxInfo {
+	gasFeeAssetId, gasFeeAssetAmount := ops.GasFeeAssetAndAmount()
 	return &txtypes.CancelOfferTxInfo{
 		AccountIndex:      ops.FromAccountIndex,
 		OfferId:           tx.OfferId,
 		GasAccountIndex:   ops.GasAccountIndex,
-		GasFeeAssetId:     ops.GasFeeAssetId,
-		GasFeeAssetAmount: ops.GasFeeAssetAmount,
+		GasFeeAssetId:     gasFeeAssetId,
+		GasFeeAssetAmount: gasFeeAssetAmount,
 		ExpiredAt:         ops.ExpiredAt,
 		Nonce:             ops.Nonce,
 	}
@@ -187,20 +200,7 @@ func VerifyCancelOfferTxSig(pubKey string, tx *types.CancelOfferTxInfo) error {
 	if err != nil {
 		return err
 	}
-
-	pk, err := parsePk(pubKey)
-	if err != nil {
-		return err
-	}
-	hFunc := mimc.NewMiMC()
-	valid, err := pk.Verify(tx.Sig, message, hFunc)
-	if err != nil {
-		return err
-	}
-	if !valid {
-		return fmt.Errorf("invalid signature")
-	}
-	return nil
+	return verifySignature(pubKey, tx.Sig, message)
 }
 
 func VerifyWithdrawNftTxSig(pubKey string, tx *types.WithdrawNftTxInfo) error {
@@ -208,20 +208,7 @@ func VerifyWithdrawNftTxSig(pubKey string, tx *types.WithdrawNftTxInfo) error {
 	if err != nil {
 		return err
 	}
-
-	pk, err := parsePk(pubKey)
-	if err != nil {
-		return err
-	}
-	hFunc := mimc.NewMiMC()
-	valid, err := pk.Verify(tx.Sig, message, hFunc)
-	if err != nil {
-		return err
-	}
-	if !valid {
-		return fmt.Errorf("invalid signature")
-	}
-	return nil
+	return verifySignature(pubKey, tx.Sig, message)
 }
 
 func VerifyTransferNftTxSig(pubKey string, tx *types.TransferNftTxInfo) error {
@@ -229,20 +216,7 @@ func VerifyTransferNftTxSig(pubKey string, tx *types.TransferNftTxInfo) error {
 	if err != nil {
 		return err
 	}
-
-	pk, err := parsePk(pubKey)
-	if err != nil {
-		return err
-	}
-	hFunc := mimc.NewMiMC()
-	valid, err := pk.Verify(tx.Sig, message, hFunc)
-	if err != nil {
-		return err
-	}
-	if !valid {
-		return fmt.Errorf("invalid signature")
-	}
-	return nil
+	return verifySignature(pubKey, tx.Sig, message)
 }
 
 func VerifyOfferTxSig(pubKey string, tx *types.OfferTxInfo) error {
@@ -251,81 +225,126 @@ func VerifyOfferTxSig(pubKey string, tx *types.OfferTxInfo) error {
 	if err != nil {
 		return err
 	}
+	return verifySignature(pubKey, tx.Sig, message)
+}
 
-	pk, err := parsePk(pubKey)
-	if err != nil {
-		return err
-	}
-	hFunc := mimc.NewMiMC()
-	valid, err := pk.Verify(tx.Sig, message, hFunc)
+func VerifyMintNftTxSig(pubKey string, tx *types.MintNftTxInfo) error {
+	message, err := tx.Hash(mimc.NewMiMC())
 	if err != nil {
 		return err
 	}
-	if !valid {
-		return fmt.Errorf("invalid signature")
-	}
-	return nil
+	return verifySignature(pubKey, tx.Sig, message)
 }
 
-func VerifyMintNftTxSig(pubKey string, tx *types.MintNftTxInfo) error {
+func VerifyCreateCollectionTxSig(pubKey string, tx *types.CreateCollectionTxInfo) error {
 	message, err := tx.Hash(mimc.NewMiMC())
 	if err != nil {
 		return err
 	}
+	return verifySignature(pubKey, tx.Sig, message)
+}
 
-	pk, err := parsePk(pubKey)
+func VerifyAtomicMatchTxSig(pubKey string, tx *types.AtomicMatchTxInfo) error {
+	message, err := tx.Hash(mimc.NewMiMC())
 	if err != nil {
 		return err
 	}
-	hFunc := mimc.NewMiMC()
-	valid, err := pk.Verify(tx.Sig, message, hFunc)
+	return verifySignature(pubKey, tx.Sig, message)
+}
+
+func VerifyTransferTxSig(pubKey string, tx *types.TransferTxInfo) error {
+	message, err := tx.Hash(mimc.NewMiMC())
 	if err != nil {
 		return err
 	}
-	if !valid {
-		return fmt.Errorf("invalid signature")
-	}
-	return nil
+	return verifySignature(pubKey, tx.Sig, message)
 }
 
-func VerifyCreateCollectionTxSig(pubKey string, tx *types.CreateCollectionTxInfo) error {
+func VerifyWithdrawTxSig(pubKey string, tx *types.WithdrawTxInfo) error {
 	message, err := tx.Hash(mimc.NewMiMC())
 	if err != nil {
 		return err
 	}
+	return verifySignature(pubKey, tx.Sig, message)
+}
 
-	pk, err := parsePk(pubKey)
+// VerifyAtomicMatchOffersSig verifies the signatures of the offers matched by the tx against the keys of their owners
+func VerifyAtomicMatchOffersSig(tx *types.AtomicMatchTxInfo, buyerPubKey, sellerPubKey string) error {
+	if tx.BuyOffer == nil || tx.SellOffer == nil {
+		return errors.New("atomic match tx without buy or sell offer")
+	}
+	message, err := tx.BuyOffer.Hash(mimc.NewMiMC())
 	if err != nil {
 		return err
 	}
-	hFunc := mimc.NewMiMC()
-	valid, err := pk.Verify(tx.Sig, message, hFunc)
+	if err := verifySignature(buyerPubKey, tx.BuyOffer.Sig, message); err != nil {
+		return fmt.Errorf("buy offer: %w", err)
+	}
+	message, err = tx.SellOffer.Hash(mimc.NewMiMC())
 	if err != nil {
 		return err
 	}
-	if !valid {
-		return fmt.Errorf("invalid signature")
+	if err := verifySignature(sellerPubKey, tx.SellOffer.Sig, message); err != nil {
+		return fmt.Errorf("sell offer: %w", err)
 	}
 	return nil
 }
 
-func VerifyAtomicMatchTxSig(pubKey string, tx *types.AtomicMatchTxInfo) error {
-	message, err := tx.Hash(mimc.NewMiMC())
+// VerifyTxSignature verifies the signature of a l2 tx or an offer given as json against pubKey.
+// An atomic match tx also needs the keys of the buyer and the seller, in this order, to verify the signatures
+// of its offers.
+func VerifyTxSignature(txType uint32, txInfo string, pubKey string, offerPubKeys ...string) error {
+	if txType == types.TxTypeOffer {
+		offer, err := types.ParseOfferTxInfo(txInfo)
+		if err != nil {
+			return err
+		}
+		return VerifyOfferTxSig(pubKey, offer)
+	}
+
+	tx, err := ParseTxInfo(txType, txInfo)
 	if err != nil {
 		return err
 	}
+	switch tx := tx.(type) {
+	case *types.TransferTxInfo:
+		return VerifyTransferTxSig(pubKey, tx)
+	case *types.WithdrawTxInfo:
+		return VerifyWithdrawTxSig(pubKey, tx)
+	case *types.CreateCollectionTxInfo:
+		return VerifyCreateCollectionTxSig(pubKey, tx)
+	case *types.MintNftTxInfo:
+		return VerifyMintNftTxSig(pubKey, tx)
+	case *types.TransferNftTxInfo:
+		return VerifyTransferNftTxSig(pubKey, tx)
+	case *types.AtomicMatchTxInfo:
+		if len(offerPubKeys) != 2 {
+			return errors.New("the keys of the buyer and the seller are needed to verify an atomic match tx")
+		}
+		if err := VerifyAtomicMatchTxSig(pubKey, tx); err != nil {
+			return err
+		}
+		return VerifyAtomicMatchOffersSig(tx, offerPubKeys[0], offerPubKeys[1])
+	case *types.CancelOfferTxInfo:
+		return VerifyCancelOfferTxSig(pubKey, tx)
+	case *types.WithdrawNftTxInfo:
+		return VerifyWithdrawNftTxSig(pubKey, tx)
+	}
+	return fmt.Errorf("tx type %d is not a l2 tx", txType)
+}
 
+func verifySignature(pubKey string, sig, message []byte) error {
 	pk, err := parsePk(pubKey)
 	if err != nil {
 		return err
 	}
 	hFunc := mimc.NewMiMC()
-	valid, err := pk.Verify(tx.Sig, message, hFunc)
+	valid, err := pk.Verify(sig, message, hFunc)
 	if err != nil {
 		return err
 	}
 	if !valid {
-		return fmt.Errorf("invalid signature")
+		return ErrInvalidSignature
 	}
 	return nil
 }
@@ -0,0 +1,148 @@
+package txutils
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/accounts"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+func testKeyManager(t *testing.T, seed string) (accounts.KeyManager, string) {
+	keyManager, err := accounts.NewSeedKeyManager(seed)
+	assert.NoError(t, err)
+	return keyManager, hex.EncodeToString(keyManager.PubKey().Bytes())
+}
+
+func testOps(fromAccountIndex int64) *types.TransactOpts {
+	toAccountNameHash, _ := AccountNameHash("gavin.legend")
+	return &types.TransactOpts{
+		FromAccountIndex:  fromAccountIndex,
+		ToAccountIndex:    3,
+		ToAccountNameHash: toAccountNameHash,
+		GasAccountIndex:   1,
+		GasFeeAssetAmount: big.NewInt(1000),
+		CallDataHash:      mimc.NewMiMC().Sum(nil),
+		ExpiredAt:         time.Now().Add(time.Hour).UnixMilli(),
+		Nonce:             1,
+	}
+}
+
+func signedOffer(t *testing.T, keyManager accounts.KeyManager, offerType, accountIndex int64) *types.SignedOffer {
+	offer := &types.OfferTxInfo{
+		Type:         offerType,
+		OfferId:      1,
+		AccountIndex: accountIndex,
+		NftIndex:     5,
+		AssetAmount:  big.NewInt(10000),
+		ListedAt:     time.Now().UnixMilli(),
+		ExpiredAt:    time.Now().Add(time.Hour).UnixMilli(),
+		TreasuryRate: 200,
+	}
+	txInfo, err := ConstructOfferTx(keyManager, offer)
+	assert.NoError(t, err)
+	signed := &types.SignedOffer{}
+	assert.NoError(t, signed.Unmarshal(txInfo))
+	return signed
+}
+
+func TestVerifyTransferAndWithdrawTxSig(t *testing.T) {
+	keyManager, pubKey := testKeyManager(t, "28e1a3762ff9944e9a4ad79477b756ef0aff3d2af76f0f40a0c3ec6ca76cf24b")
+	_, otherPubKey := testKeyManager(t, "17673b9a9fdec6dc90c7cc1eb1c939134dfb659d2f08edbe071e5c45f343d008")
+
+	transfer, err := ConstructTransferTx(keyManager, testOps(2), &types.TransferTxReq{
+		ToAccountName: "gavin.legend",
+		AssetAmount:   big.NewInt(100),
+	})
+	assert.NoError(t, err)
+	transferTx, err := types.ParseTransferTxInfo(transfer)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyTransferTxSig(pubKey, transferTx))
+	assert.ErrorIs(t, VerifyTransferTxSig(otherPubKey, transferTx), ErrInvalidSignature)
+	assert.NoError(t, VerifyTxSignature(types.TxTypeTransfer, transfer, pubKey))
+
+	withdraw, err := ConstructWithdrawTxInfo(keyManager, &types.WithdrawReq{
+		AssetAmount: big.NewInt(100),
+		ToAddress:   "0x8b2C5A5744F42AA9269BaabDd05933a96D8EF911",
+	}, testOps(2))
+	assert.NoError(t, err)
+	withdrawTx, err := types.ParseWithdrawTxInfo(withdraw)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyWithdrawTxSig(pubKey, withdrawTx))
+	assert.ErrorIs(t, VerifyTxSignature(types.TxTypeWithdraw, withdraw, otherPubKey), ErrInvalidSignature)
+
+	// a tampered tx doesn't match its signature anymore
+	withdrawTx.AssetAmount = big.NewInt(1000)
+	assert.ErrorIs(t, VerifyWithdrawTxSig(pubKey, withdrawTx), ErrInvalidSignature)
+}
+
+func TestVerifyTxSignature(t *testing.T) {
+	keyManager, pubKey := testKeyManager(t, "28e1a3762ff9944e9a4ad79477b756ef0aff3d2af76f0f40a0c3ec6ca76cf24b")
+	_, otherPubKey := testKeyManager(t, "17673b9a9fdec6dc90c7cc1eb1c939134dfb659d2f08edbe071e5c45f343d008")
+
+	txInfos := map[uint32]func() (string, error){
+		types.TxTypeCreateCollection: func() (string, error) {
+			return ConstructCreateCollectionTx(keyManager, &types.CreateCollectionReq{Name: "collection"}, testOps(2))
+		},
+		types.TxTypeMintNft: func() (string, error) {
+			return ConstructMintNftTx(keyManager, &types.MintNftTxReq{
+				NftContentHash:  NftContentHash("content"),
+				NftCollectionId: 1,
+			}, testOps(2))
+		},
+		types.TxTypeTransferNft: func() (string, error) {
+			return ConstructTransferNftTx(keyManager, &types.TransferNftTxReq{NftIndex: 5}, testOps(2))
+		},
+		types.TxTypeWithdrawNft: func() (string, error) {
+			return ConstructWithdrawNftTx(keyManager, &types.WithdrawNftTxReq{
+				AccountIndex: 2,
+				NftIndex:     5,
+				ToAddress:    "0x8b2C5A5744F42AA9269BaabDd05933a96D8EF911",
+			}, testOps(2))
+		},
+		types.TxTypeCancelOffer: func() (string, error) {
+			return ConstructCancelOfferTx(keyManager, &types.CancelOfferReq{OfferId: 1}, testOps(2))
+		},
+		types.TxTypeOffer: func() (string, error) {
+			return types.MarshalTxInfo(signedOffer(t, keyManager, types.SellOfferType, 2))
+		},
+	}
+	for txType, construct := range txInfos {
+		txInfo, err := construct()
+		assert.NoError(t, err, txType)
+		assert.NoError(t, VerifyTxSignature(txType, txInfo, pubKey), txType)
+		assert.ErrorIs(t, VerifyTxSignature(txType, txInfo, otherPubKey), ErrInvalidSignature, txType)
+	}
+
+	assert.EqualError(t, VerifyTxSignature(types.TxTypeDeposit, "{}", pubKey), "tx type 2 is not a l2 tx")
+}
+
+func TestVerifyAtomicMatchTxSig(t *testing.T) {
+	submitter, submitterPubKey := testKeyManager(t, "28e1a3762ff9944e9a4ad79477b756ef0aff3d2af76f0f40a0c3ec6ca76cf24b")
+	buyer, buyerPubKey := testKeyManager(t, "17673b9a9fdec6dc90c7cc1eb1c939134dfb659d2f08edbe071e5c45f343d008")
+	seller, sellerPubKey := testKeyManager(t, "aa6a31bbd5d2c6bd2fd3e6a1f1f4d1f3f9e2e1b0c5a8b5d2f1c0e9d8c7b6a596")
+
+	txInfo, err := ConstructAtomicMatchTx(submitter, &types.AtomicMatchTxReq{
+		BuyOffer:  signedOffer(t, buyer, types.BuyOfferType, 3),
+		SellOffer: signedOffer(t, seller, types.SellOfferType, 4),
+	}, testOps(2))
+	assert.NoError(t, err)
+
+	assert.NoError(t, VerifyTxSignature(types.TxTypeAtomicMatch, txInfo, submitterPubKey, buyerPubKey, sellerPubKey))
+	assert.Error(t, VerifyTxSignature(types.TxTypeAtomicMatch, txInfo, submitterPubKey))
+	assert.ErrorIs(t, VerifyTxSignature(types.TxTypeAtomicMatch, txInfo, buyerPubKey, buyerPubKey, sellerPubKey), ErrInvalidSignature)
+
+	err = VerifyTxSignature(types.TxTypeAtomicMatch, txInfo, submitterPubKey, sellerPubKey, buyerPubKey)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	assert.EqualError(t, err, "buy offer: invalid signature")
+
+	tx, err := types.ParseAtomicMatchTxInfo(txInfo)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyAtomicMatchTxSig(submitterPubKey, tx))
+	assert.EqualError(t, VerifyAtomicMatchOffersSig(tx, buyerPubKey, submitterPubKey), "sell offer: invalid signature")
+}
@@ -0,0 +1,72 @@
+package types
+
+import (
+	"errors"
+	"math/big"
+)
+
+var (
+	// ErrAmountWithoutAsset is returned for an Amount whose Asset is not set
+	ErrAmountWithoutAsset = errors.New("the asset of the amount is not set")
+	// ErrAmountWithoutValue is returned for an Amount whose Value is not set
+	ErrAmountWithoutValue = errors.New("the value of the amount is not set")
+)
+
+// Amount is an amount of an asset in its base unit, the amount package parses and formats it with the decimals
+// of the asset.
+type Amount struct {
+	Asset *Asset
+	Value *big.Int
+}
+
+// Validate checks that the asset and the value of the amount are set
+func (a *Amount) Validate() error {
+	if a.Asset == nil {
+		return ErrAmountWithoutAsset
+	}
+	if a.Value == nil {
+		return ErrAmountWithoutValue
+	}
+	return nil
+}
+
+// assetId returns the id of the asset of the amount, or an invalid asset id when the asset is not set, so that a tx
+// signed with it fails its validation
+func (a *Amount) assetId() int64 {
+	if a.Asset == nil {
+		return -1
+	}
+	return int64(a.Asset.Id)
+}
+
+// AssetAndAmount returns the asset id and the amount of the transfer, taken from Amount when it is set
+func (tx *TransferTxReq) AssetAndAmount() (int64, *big.Int) {
+	if tx.Amount != nil {
+		return tx.Amount.assetId(), tx.Amount.Value
+	}
+	return tx.AssetId, tx.AssetAmount
+}
+
+// AssetAndAmount returns the asset id and the amount of the withdrawal, taken from Amount when it is set
+func (tx *WithdrawReq) AssetAndAmount() (int64, *big.Int) {
+	if tx.Amount != nil {
+		return tx.Amount.assetId(), tx.Amount.Value
+	}
+	return tx.AssetId, tx.AssetAmount
+}
+
+// AssetAndAmount returns the asset id and the amount of the offer, taken from Amount when it is set
+func (tx *OfferReq) AssetAndAmount() (int64, *big.Int) {
+	if tx.Amount != nil {
+		return tx.Amount.assetId(), tx.Amount.Value
+	}
+	return tx.AssetId, tx.AssetAmount
+}
+
+// GasFeeAssetAndAmount returns the asset id and the amount of the gas fee, taken from GasFee when it is set
+func (ops *TransactOpts) GasFeeAssetAndAmount() (int64, *big.Int) {
+	if ops.GasFee != nil {
+		return ops.GasFee.assetId(), ops.GasFee.Value
+	}
+	return ops.GasFeeAssetId, ops.GasFeeAssetAmount
+}
@@ -0,0 +1,18 @@
+package types
+
+// Error codes returned by the ZkBNB api server in the code field of the error responses, as defined by the
+// error table of the ZkBNB server (types/errors.go of github.com/bnb-chain/zkbnb). Only the codes the sdk relies on
+// are listed, the other errors, e.g. an invalid signature, an nft not owned or an offer id already used, are told
+// apart by their message.
+const (
+	CodeInvalidParam   = 20001
+	CodeInvalidTxField = 20002
+
+	CodeInvalidExpireTime = 21000
+	CodeInvalidGasFee     = 21002
+	CodeBalanceNotEnough  = 21003
+	CodeInvalidNonce      = 21010
+
+	CodeNotFound = 29404
+	CodeInternal = 29500
+)
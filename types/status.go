@@ -0,0 +1,161 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// TxStatus is the status of a l2 tx
+type TxStatus int64
+
+const (
+	TxStatusFailed TxStatus = iota
+	TxStatusPending
+	TxStatusExecuted
+	TxStatusPacked
+	TxStatusCommitted
+	TxStatusVerified
+)
+
+var txStatusNames = []string{"failed", "pending", "executed", "packed", "committed", "verified"}
+
+func (s TxStatus) String() string {
+	return statusName(txStatusNames, int64(s))
+}
+
+// IsFinal reports whether the tx can't change anymore
+func (s TxStatus) IsFinal() bool {
+	return s == TxStatusFailed || s == TxStatusVerified
+}
+
+// IsFailed reports whether the tx failed to be executed
+func (s TxStatus) IsFailed() bool {
+	return s == TxStatusFailed
+}
+
+// MarshalJSON encodes the status as a number, like the ZkBNB api does
+func (s TxStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(int64(s))
+}
+
+// UnmarshalJSON decodes the status from a number or a name
+func (s *TxStatus) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalStatus(txStatusNames, data)
+	*s = TxStatus(v)
+	return err
+}
+
+// BlockStatus is the status of a l2 block
+type BlockStatus int64
+
+const (
+	BlockStatusProposing BlockStatus = iota
+	BlockStatusPending
+	BlockStatusCommitted
+	BlockStatusVerified
+)
+
+var blockStatusNames = []string{"proposing", "pending", "committed", "verified"}
+
+func (s BlockStatus) String() string {
+	return statusName(blockStatusNames, int64(s))
+}
+
+// IsFinal reports whether the block is verified on l1
+func (s BlockStatus) IsFinal() bool {
+	return s == BlockStatusVerified
+}
+
+// IsCommitted reports whether the block is committed on l1, it may be verified already
+func (s BlockStatus) IsCommitted() bool {
+	return s >= BlockStatusCommitted
+}
+
+// MarshalJSON encodes the status as a number, like the ZkBNB api does
+func (s BlockStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(int64(s))
+}
+
+// UnmarshalJSON decodes the status from a number or a name
+func (s *BlockStatus) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalStatus(blockStatusNames, data)
+	*s = BlockStatus(v)
+	return err
+}
+
+// AccountStatus is the status of a l2 account
+type AccountStatus int64
+
+const (
+	// AccountStatusPending is the status of an account registered on l1 but not created on l2 yet
+	AccountStatusPending AccountStatus = iota
+	AccountStatusConfirmed
+)
+
+var accountStatusNames = []string{"pending", "confirmed"}
+
+func (s AccountStatus) String() string {
+	return statusName(accountStatusNames, int64(s))
+}
+
+// IsFinal reports whether the account is created on l2
+func (s AccountStatus) IsFinal() bool {
+	return s == AccountStatusConfirmed
+}
+
+// MarshalJSON encodes the status as a number, like the ZkBNB api does
+func (s AccountStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(int64(s))
+}
+
+// UnmarshalJSON decodes the status from a number or a name
+func (s *AccountStatus) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalStatus(accountStatusNames, data)
+	*s = AccountStatus(v)
+	return err
+}
+
+func statusName(names []string, v int64) string {
+	if v >= 0 && v < int64(len(names)) {
+		return names[v]
+	}
+	return "unknown(" + strconv.FormatInt(v, 10) + ")"
+}
+
+func unmarshalStatus(names []string, data []byte) (int64, error) {
+	var v int64
+	if err := json.Unmarshal(data, &v); err == nil {
+		return v, nil
+	}
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return 0, fmt.Errorf("invalid status %s", data)
+	}
+	for i, n := range names {
+		if n == name {
+			return int64(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown status %q", name)
+}
+
+// TxStatus returns the typed status of the tx
+func (t *Tx) TxStatus() TxStatus {
+	return TxStatus(t.Status)
+}
+
+// BlockStatus returns the typed status of the block
+func (b *Block) BlockStatus() BlockStatus {
+	return BlockStatus(b.Status)
+}
+
+// AccountStatus returns the typed status of the account
+func (a *Account) AccountStatus() AccountStatus {
+	return AccountStatus(a.Status)
+}
+
+// AccountStatus returns the typed status of the gas account
+func (a *GasAccount) AccountStatus() AccountStatus {
+	return AccountStatus(a.Status)
+}
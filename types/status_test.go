@@ -0,0 +1,65 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxStatus(t *testing.T) {
+	assert.Equal(t, "committed", TxStatusCommitted.String())
+	assert.Equal(t, "unknown(9)", TxStatus(9).String())
+	assert.True(t, TxStatusFailed.IsFinal())
+	assert.True(t, TxStatusFailed.IsFailed())
+	assert.True(t, TxStatusVerified.IsFinal())
+	assert.False(t, TxStatusVerified.IsFailed())
+	assert.False(t, TxStatusPacked.IsFinal())
+
+	tx := &Tx{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"hash":"hash","status":4}`), tx))
+	assert.Equal(t, TxStatusCommitted, tx.TxStatus())
+}
+
+func TestStatusJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Tx      TxStatus      `json:"tx"`
+		Block   BlockStatus   `json:"block"`
+		Account AccountStatus `json:"account"`
+	}{TxStatusExecuted, BlockStatusVerified, AccountStatusConfirmed})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"tx":2,"block":3,"account":1}`, string(data))
+
+	var statuses struct {
+		Tx      TxStatus      `json:"tx"`
+		Block   BlockStatus   `json:"block"`
+		Account AccountStatus `json:"account"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &statuses))
+	assert.Equal(t, TxStatusExecuted, statuses.Tx)
+	assert.Equal(t, BlockStatusVerified, statuses.Block)
+	assert.Equal(t, AccountStatusConfirmed, statuses.Account)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"tx":"verified","block":"committed","account":"pending"}`), &statuses))
+	assert.Equal(t, TxStatusVerified, statuses.Tx)
+	assert.Equal(t, BlockStatusCommitted, statuses.Block)
+	assert.Equal(t, AccountStatusPending, statuses.Account)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"tx":"lost"}`), &statuses))
+	assert.Error(t, json.Unmarshal([]byte(`{"tx":true}`), &statuses))
+}
+
+func TestBlockAndAccountStatus(t *testing.T) {
+	block := &Block{Status: int64(BlockStatusCommitted)}
+	assert.Equal(t, BlockStatusCommitted, block.BlockStatus())
+	assert.True(t, block.BlockStatus().IsCommitted())
+	assert.False(t, block.BlockStatus().IsFinal())
+	assert.True(t, BlockStatusVerified.IsCommitted())
+	assert.Equal(t, "proposing", BlockStatusProposing.String())
+
+	account := &Account{Status: 1}
+	assert.Equal(t, AccountStatusConfirmed, account.AccountStatus())
+	assert.True(t, account.AccountStatus().IsFinal())
+	gasAccount := &GasAccount{Status: 0}
+	assert.Equal(t, "pending", gasAccount.AccountStatus().String())
+}
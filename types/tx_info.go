@@ -0,0 +1,100 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownTxType is returned when decoding the info of a tx with an unknown type
+var ErrUnknownTxType = errors.New("unknown tx type")
+
+// TxInfo is the decoded info of a tx, it is one of the XxxTxInfo pointers matching the tx type.
+type TxInfo interface {
+	GetTxType() int
+}
+
+// EmptyTxInfo is the info of the empty txs padding the blocks
+type EmptyTxInfo struct{}
+
+func (txInfo *EmptyTxInfo) GetTxType() int {
+	return TxTypeEmpty
+}
+
+func (txInfo *RegisterZnsTxInfo) GetTxType() int {
+	return TxTypeRegisterZns
+}
+
+func (txInfo *DepositTxInfo) GetTxType() int {
+	return TxTypeDeposit
+}
+
+func (txInfo *DepositNftTxInfo) GetTxType() int {
+	return TxTypeDepositNft
+}
+
+func (txInfo *FullExitTxInfo) GetTxType() int {
+	return TxTypeFullExit
+}
+
+func (txInfo *FullExitNftTxInfo) GetTxType() int {
+	return TxTypeFullExitNft
+}
+
+func (txInfo *OfferTxInfo) GetTxType() int {
+	return TxTypeOffer
+}
+
+// DecodeTxInfo decodes the info of the tx according to its type
+func DecodeTxInfo(tx *Tx) (TxInfo, error) {
+	txInfo, err := decodeTxInfo(tx.Type, tx.Info)
+	if err != nil {
+		return nil, fmt.Errorf("decode info of tx %s: %w", tx.Hash, err)
+	}
+	return txInfo, nil
+}
+
+// DecodeInfo decodes the info of the tx according to its type
+func (t *Tx) DecodeInfo() (TxInfo, error) {
+	return DecodeTxInfo(t)
+}
+
+func decodeTxInfo(txType int64, info string) (txInfo TxInfo, err error) {
+	switch txType {
+	case TxTypeEmpty:
+		return &EmptyTxInfo{}, nil
+	case TxTypeRegisterZns:
+		txInfo, err = ParseRegisterZnsTxInfo(info)
+	case TxTypeDeposit:
+		txInfo, err = ParseDepositTxInfo(info)
+	case TxTypeDepositNft:
+		txInfo, err = ParseDepositNftTxInfo(info)
+	case TxTypeTransfer:
+		txInfo, err = ParseTransferTxInfo(info)
+	case TxTypeWithdraw:
+		txInfo, err = ParseWithdrawTxInfo(info)
+	case TxTypeCreateCollection:
+		txInfo, err = ParseCreateCollectionTxInfo(info)
+	case TxTypeMintNft:
+		txInfo, err = ParseMintNftTxInfo(info)
+	case TxTypeTransferNft:
+		txInfo, err = ParseTransferNftTxInfo(info)
+	case TxTypeAtomicMatch:
+		txInfo, err = ParseAtomicMatchTxInfo(info)
+	case TxTypeCancelOffer:
+		txInfo, err = ParseCancelOfferTxInfo(info)
+	case TxTypeWithdrawNft:
+		txInfo, err = ParseWithdrawNftTxInfo(info)
+	case TxTypeFullExit:
+		txInfo, err = ParseFullExitTxInfo(info)
+	case TxTypeFullExitNft:
+		txInfo, err = ParseFullExitNftTxInfo(info)
+	case TxTypeOffer:
+		txInfo, err = ParseOfferTxInfo(info)
+	default:
+		return nil, fmt.Errorf("%w %d", ErrUnknownTxType, txType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return txInfo, nil
+}
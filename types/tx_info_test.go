@@ -0,0 +1,59 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/bnb-chain/zkbnb-crypto/wasm/txtypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeTxInfo(t *testing.T) {
+	txInfos := []TxInfo{
+		&EmptyTxInfo{},
+		&RegisterZnsTxInfo{TxType: TxTypeRegisterZns, AccountIndex: 2, AccountName: "sher"},
+		&DepositTxInfo{TxType: TxTypeDeposit, AccountIndex: 2, AssetAmount: big.NewInt(100)},
+		&DepositNftTxInfo{TxType: TxTypeDepositNft, AccountIndex: 2, NftIndex: 3},
+		&TransferTxInfo{FromAccountIndex: 2, ToAccountIndex: 3, AssetAmount: big.NewInt(100), GasFeeAssetAmount: big.NewInt(1)},
+		&WithdrawTxInfo{FromAccountIndex: 2, AssetAmount: big.NewInt(100), GasFeeAssetAmount: big.NewInt(1)},
+		&CreateCollectionTxInfo{AccountIndex: 2, Name: "collection", GasFeeAssetAmount: big.NewInt(1)},
+		&MintNftTxInfo{CreatorAccountIndex: 2, NftContentHash: "hash", GasFeeAssetAmount: big.NewInt(1)},
+		&TransferNftTxInfo{FromAccountIndex: 2, NftIndex: 3, GasFeeAssetAmount: big.NewInt(1)},
+		&AtomicMatchTxInfo{AccountIndex: 2, BuyOffer: &txtypes.OfferTxInfo{OfferId: 1}, SellOffer: &txtypes.OfferTxInfo{OfferId: 2}},
+		&CancelOfferTxInfo{AccountIndex: 2, OfferId: 1, GasFeeAssetAmount: big.NewInt(1)},
+		&WithdrawNftTxInfo{AccountIndex: 2, NftIndex: 3, GasFeeAssetAmount: big.NewInt(1)},
+		&FullExitTxInfo{TxType: TxTypeFullExit, AccountIndex: 2, AssetAmount: big.NewInt(100)},
+		&FullExitNftTxInfo{TxType: TxTypeFullExitNft, AccountIndex: 2, NftIndex: 3},
+		&OfferTxInfo{OfferId: 1, AccountIndex: 2, AssetAmount: big.NewInt(100)},
+	}
+	for txType, txInfo := range txInfos {
+		assert.Equal(t, txType, txInfo.GetTxType())
+		info, err := MarshalTxInfo(txInfo)
+		assert.NoError(t, err)
+
+		tx := &Tx{Hash: "hash", Type: int64(txType), Info: info}
+		decoded, err := DecodeTxInfo(tx)
+		assert.NoError(t, err)
+		assert.IsType(t, txInfo, decoded)
+		assert.Equal(t, txInfo, decoded)
+	}
+}
+
+func TestDecodeTxInfoMethod(t *testing.T) {
+	tx := &EnrichedTx{Tx: Tx{Type: TxTypeDeposit, Info: `{"TxType":2,"AccountIndex":5,"AssetId":1,"AssetAmount":1000}`}}
+	txInfo, err := tx.DecodeInfo()
+	assert.NoError(t, err)
+	deposit, ok := txInfo.(*DepositTxInfo)
+	assert.True(t, ok)
+	assert.Equal(t, int64(5), deposit.AccountIndex)
+	assert.Equal(t, big.NewInt(1000), deposit.AssetAmount)
+}
+
+func TestDecodeTxInfoErrors(t *testing.T) {
+	_, err := DecodeTxInfo(&Tx{Hash: "hash", Type: 64, Info: "{}"})
+	assert.ErrorIs(t, err, ErrUnknownTxType)
+	assert.EqualError(t, err, "decode info of tx hash: unknown tx type 64")
+
+	_, err = DecodeTxInfo(&Tx{Hash: "hash", Type: TxTypeTransfer, Info: "not json"})
+	assert.Error(t, err)
+}
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"encoding/json"
+	"errors"
 	"math/big"
 )
 
@@ -17,9 +19,33 @@ type OfferTxInfo struct {
 	Sig          []byte
 }
 
+// SignedOffer is an offer signed by its account, it is shared as json between the buyer, the seller and the matcher
+// of an atomic match.
+type SignedOffer struct {
+	OfferTxInfo
+}
+
+// Marshal returns the json of the offer.
+func (o *SignedOffer) Marshal() (string, error) {
+	return MarshalTxInfo(o)
+}
+
+// Unmarshal sets the offer from its json, the offer must be signed.
+func (o *SignedOffer) Unmarshal(data string) error {
+	offer := &SignedOffer{}
+	if err := json.Unmarshal([]byte(data), offer); err != nil {
+		return err
+	}
+	if len(offer.Sig) == 0 {
+		return errors.New("the offer is not signed")
+	}
+	*o = *offer
+	return nil
+}
+
 type AtomicMatchTxReq struct {
-	BuyOffer  *OfferTxInfo
-	SellOffer *OfferTxInfo
+	BuyOffer  *SignedOffer
+	SellOffer *SignedOffer
 }
 
 type CancelOfferReq struct {
@@ -36,6 +62,12 @@ type MintNftTxReq struct {
 	NftContentHash      string
 	NftCollectionId     int64
 	CreatorTreasuryRate int64
+
+	// NftCID, NftContent and NftMetadata are sources of the content hash when NftContentHash is empty, an IPFS CID,
+	// the raw content or the metadata JSON, only one of them can be set, see txutils.ResolveNftContent
+	NftCID      string      `json:",omitempty"`
+	NftContent  []byte      `json:",omitempty"`
+	NftMetadata interface{} `json:",omitempty"`
 }
 
 type TransferNftTxReq struct {
@@ -47,6 +79,8 @@ type TransferTxReq struct {
 	ToAccountName string
 	AssetId       int64
 	AssetAmount   *big.Int
+	// Amount is optional, AssetId and AssetAmount are ignored when it is set
+	Amount *Amount `json:",omitempty"`
 }
 
 type WithdrawNftTxReq struct {
@@ -59,4 +93,29 @@ type WithdrawReq struct {
 	AssetId     int64
 	AssetAmount *big.Int
 	ToAddress   string
+	// Amount is optional, AssetId and AssetAmount are ignored when it is set
+	Amount *Amount `json:",omitempty"`
+}
+
+type OfferReq struct {
+	Type        int64
+	NftIndex    int64
+	AssetId     int64
+	AssetAmount *big.Int
+	// Amount is optional, AssetId and AssetAmount are ignored when it is set
+	Amount *Amount
+
+	// Optional
+	AccountIndex int64
+	OfferId      int64
+	ListedAt     int64
+	ExpiredAt    int64
+	// TreasuryRate is the share of the amount paid to the treasury, in 1/10000. The default rate of the client
+	// is used when it is nil, an explicit 0 is kept, see NewTreasuryRate
+	TreasuryRate *int64
+}
+
+// NewTreasuryRate returns a pointer to the treasury rate, for OfferReq.TreasuryRate
+func NewTreasuryRate(rate int64) *int64 {
+	return &rate
 }
@@ -0,0 +1,27 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignedOfferJSON(t *testing.T) {
+	offer := &SignedOffer{OfferTxInfo{Type: SellOfferType, OfferId: 2, AccountIndex: 3, AssetAmount: big.NewInt(100), Sig: []byte{1, 2}}}
+	data, err := offer.Marshal()
+	assert.NoError(t, err)
+	// the json is the one of the offer tx info
+	info, err := ParseOfferTxInfo(data)
+	assert.NoError(t, err)
+	assert.Equal(t, &offer.OfferTxInfo, info)
+
+	parsed := &SignedOffer{}
+	assert.NoError(t, parsed.Unmarshal(data))
+	assert.Equal(t, offer, parsed)
+
+	unsigned, err := MarshalTxInfo(&OfferTxInfo{Type: SellOfferType, OfferId: 2, AssetAmount: big.NewInt(100)})
+	assert.NoError(t, err)
+	assert.EqualError(t, parsed.Unmarshal(unsigned), "the offer is not signed")
+	assert.Equal(t, offer, parsed)
+}
@@ -52,6 +52,8 @@ type TransactOpts struct {
 	ExpiredAt         int64
 	Nonce             int64
 	Memo              string
+	// GasFee is optional, GasFeeAssetId and GasFeeAssetAmount are ignored when it is set
+	GasFee *Amount `json:",omitempty"`
 
 	// Optional
 	ToAccountIndex    int64
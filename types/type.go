@@ -144,6 +144,23 @@ type TxHash struct {
 	TxHash string `json:"tx_hash"`
 }
 
+// RawTx is a signed tx posted to the sendTxs api
+type RawTx struct {
+	TxType uint32 `json:"tx_type"`
+	TxInfo string `json:"tx_info"`
+}
+
+// SendTxResult is the outcome of one of the txs posted to the sendTxs api, Code is 0 when the tx is accepted
+type SendTxResult struct {
+	TxHash  string `json:"tx_hash"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type SendTxResults struct {
+	Results []*SendTxResult `json:"results"`
+}
+
 type NextNonce struct {
 	Nonce uint64 `json:"nonce"`
 }
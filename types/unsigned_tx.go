@@ -0,0 +1,120 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// UnsignedTxVersion is the version of the unsigned tx format written by this sdk
+const UnsignedTxVersion = 1
+
+// UnsignedTx is a tx request with its resolved ops, it can be signed on a machine without access to ZkBNB.
+// Req is a *TransferTxReq, *WithdrawReq, *MintNftTxReq, *TransferNftTxReq, *WithdrawNftTxReq,
+// *CreateCollectionReq, *CancelOfferReq or *AtomicMatchTxReq.
+type UnsignedTx struct {
+	Version int
+	TxType  uint32
+	Req     interface{}
+	Ops     *TransactOpts
+}
+
+type unsignedTxJSON struct {
+	Version int             `json:"version"`
+	TxType  uint32          `json:"tx_type"`
+	Req     json.RawMessage `json:"req"`
+	Ops     *TransactOpts   `json:"ops"`
+}
+
+func (tx *UnsignedTx) MarshalJSON() ([]byte, error) {
+	req, err := json.Marshal(tx.Req)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&unsignedTxJSON{Version: tx.Version, TxType: tx.TxType, Req: req, Ops: tx.Ops})
+}
+
+func (tx *UnsignedTx) UnmarshalJSON(data []byte) error {
+	raw := &unsignedTxJSON{}
+	if err := json.Unmarshal(data, raw); err != nil {
+		return err
+	}
+	if raw.Version != UnsignedTxVersion {
+		return fmt.Errorf("unsupported unsigned tx version %d", raw.Version)
+	}
+	req, err := newTxReq(raw.TxType)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw.Req, req); err != nil {
+		return err
+	}
+	*tx = UnsignedTx{Version: raw.Version, TxType: raw.TxType, Req: req, Ops: raw.Ops}
+	return nil
+}
+
+// ParseUnsignedTx parses an unsigned tx written by MarshalUnsignedTx
+func ParseUnsignedTx(data []byte) (*UnsignedTx, error) {
+	tx := &UnsignedTx{}
+	if err := json.Unmarshal(data, tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// MarshalUnsignedTx returns the portable json form of the unsigned tx
+func MarshalUnsignedTx(tx *UnsignedTx) ([]byte, error) {
+	return json.MarshalIndent(tx, "", "  ")
+}
+
+// ReqTxType returns the tx type of a tx request, e.g. TxTypeTransfer for a *TransferTxReq
+func ReqTxType(req interface{}) (uint32, error) {
+	switch req.(type) {
+	case *TransferTxReq:
+		return TxTypeTransfer, nil
+	case *WithdrawReq:
+		return TxTypeWithdraw, nil
+	case *CreateCollectionReq:
+		return TxTypeCreateCollection, nil
+	case *MintNftTxReq:
+		return TxTypeMintNft, nil
+	case *TransferNftTxReq:
+		return TxTypeTransferNft, nil
+	case *AtomicMatchTxReq:
+		return TxTypeAtomicMatch, nil
+	case *CancelOfferReq:
+		return TxTypeCancelOffer, nil
+	case *WithdrawNftTxReq:
+		return TxTypeWithdrawNft, nil
+	}
+	return 0, fmt.Errorf("unsupported tx request %T", req)
+}
+
+func newTxReq(txType uint32) (interface{}, error) {
+	switch txType {
+	case TxTypeTransfer:
+		return &TransferTxReq{}, nil
+	case TxTypeWithdraw:
+		return &WithdrawReq{}, nil
+	case TxTypeCreateCollection:
+		return &CreateCollectionReq{}, nil
+	case TxTypeMintNft:
+		return &MintNftTxReq{}, nil
+	case TxTypeTransferNft:
+		return &TransferNftTxReq{}, nil
+	case TxTypeAtomicMatch:
+		return &AtomicMatchTxReq{}, nil
+	case TxTypeCancelOffer:
+		return &CancelOfferReq{}, nil
+	case TxTypeWithdrawNft:
+		return &WithdrawNftTxReq{}, nil
+	}
+	return nil, fmt.Errorf("unsupported tx type %d", txType)
+}
+
+// SignedTx is a signed tx which is not sent yet, it is sent with SendRawTx(TxType, TxInfo)
+type SignedTx struct {
+	TxType uint32        `json:"tx_type"`
+	TxInfo string        `json:"tx_info"`
+	Hash   string        `json:"hash"`
+	Ops    *TransactOpts `json:"ops,omitempty"`
+}
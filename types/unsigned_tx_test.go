@@ -0,0 +1,51 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnsignedTxJSON(t *testing.T) {
+	reqs := []interface{}{
+		&TransferTxReq{ToAccountName: "gavin.legend", AssetId: 1, AssetAmount: big.NewInt(100)},
+		&WithdrawReq{AssetAmount: big.NewInt(100), ToAddress: "0x8b2C5A5744F42AA9269BaabDd05933a96D8EF911"},
+		&CreateCollectionReq{Name: "collection", Introduction: "introduction"},
+		&MintNftTxReq{To: "gavin.legend", NftContentHash: "hash", NftCollectionId: 1, CreatorTreasuryRate: 10},
+		&TransferNftTxReq{To: "gavin.legend", NftIndex: 3},
+		&AtomicMatchTxReq{
+			BuyOffer:  &SignedOffer{OfferTxInfo{Type: BuyOfferType, OfferId: 1, AssetAmount: big.NewInt(100), Sig: []byte{1, 2}}},
+			SellOffer: &SignedOffer{OfferTxInfo{Type: SellOfferType, OfferId: 2, AssetAmount: big.NewInt(100), Sig: []byte{3, 4}}},
+		},
+		&CancelOfferReq{OfferId: 1},
+		&WithdrawNftTxReq{AccountIndex: 2, NftIndex: 3, ToAddress: "0x8b2C5A5744F42AA9269BaabDd05933a96D8EF911"},
+	}
+	for _, req := range reqs {
+		txType, err := ReqTxType(req)
+		require.NoError(t, err)
+		tx := &UnsignedTx{
+			Version: UnsignedTxVersion,
+			TxType:  txType,
+			Req:     req,
+			Ops:     &TransactOpts{TxType: int(txType), FromAccountIndex: 2, GasFeeAssetAmount: big.NewInt(1), CallDataHash: []byte{5}, Nonce: 7},
+		}
+		data, err := MarshalUnsignedTx(tx)
+		require.NoError(t, err)
+		parsed, err := ParseUnsignedTx(data)
+		require.NoError(t, err)
+		assert.Equal(t, tx, parsed)
+	}
+}
+
+func TestParseUnsignedTxErrors(t *testing.T) {
+	_, err := ParseUnsignedTx([]byte(`{"version":2,"tx_type":4,"req":{},"ops":{}}`))
+	assert.EqualError(t, err, "unsupported unsigned tx version 2")
+
+	_, err = ParseUnsignedTx([]byte(`{"version":1,"tx_type":1,"req":{},"ops":{}}`))
+	assert.EqualError(t, err, "unsupported tx type 1")
+
+	_, err = ReqTxType(&OfferReq{})
+	assert.EqualError(t, err, "unsupported tx request *types.OfferReq")
+}
@@ -0,0 +1,496 @@
+package zkbnbtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+// ServeHTTP serves the ZkBNB api backed by the ledger
+func (l *Ledger) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	l.mu.Lock()
+	handler, ok := l.handlers[r.URL.Path]
+	l.mu.Unlock()
+	if ok {
+		handler(w, r)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		writeError(w, types.CodeInvalidParam, "invalid param: "+err.Error())
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var result interface{}
+	var err *apiError
+	switch r.URL.Path {
+	case "/api/v1/currentHeight":
+		result = &types.CurrentHeight{Height: l.currentHeight()}
+	case "/api/v1/layer2BasicInfo":
+		result = l.layer2BasicInfo()
+	case "/api/v1/search":
+		result, err = l.search(r.Form.Get("keyword"))
+	case "/api/v1/account":
+		result, err = l.getAccount(r.Form.Get("by"), r.Form.Get("value"))
+	case "/api/v1/accounts":
+		result, err = l.getAccounts(r)
+	case "/api/v1/asset":
+		result, err = l.getAsset(r.Form.Get("by"), r.Form.Get("value"))
+	case "/api/v1/assets":
+		result, err = l.getAssets(r)
+	case "/api/v1/gasFeeAssets":
+		result = l.getGasFeeAssets()
+	case "/api/v1/gasFee":
+		result, err = l.getGasFee(r)
+	case "/api/v1/gasAccount":
+		result = l.gasAccount
+	case "/api/v1/nextNonce":
+		result, err = l.getNextNonce(r)
+	case "/api/v1/maxOfferId":
+		result, err = l.getMaxOfferId(r)
+	case "/api/v1/block":
+		result, err = l.getBlock(r.Form.Get("by"), r.Form.Get("value"))
+	case "/api/v1/blocks":
+		result, err = l.getBlocks(r)
+	case "/api/v1/blockTxs":
+		result, err = l.getBlockTxs(r)
+	case "/api/v1/tx":
+		result, err = l.getTx(r.Form.Get("hash"))
+	case "/api/v1/txs":
+		result, err = l.getTxs(r, func(tx *types.EnrichedTx) bool { return true })
+	case "/api/v1/pendingTxs":
+		result, err = l.getTxs(r, isPending)
+	case "/api/v1/executedTxs":
+		result, err = l.getTxs(r, func(tx *types.EnrichedTx) bool { return tx.TxStatus() == types.TxStatusExecuted })
+	case "/api/v1/accountTxs":
+		result, err = l.getAccountTxs(r, func(tx *types.EnrichedTx) bool { return true })
+	case "/api/v1/accountPendingTxs":
+		result, err = l.getAccountTxs(r, isPending)
+	case "/api/v1/accountNfts":
+		result, err = l.getAccountNfts(r)
+	case "/api/v1/sendTx":
+		if r.Method != http.MethodPost {
+			err = invalidParam("sendTx must be posted")
+			break
+		}
+		result, err = l.postTx(r.PostForm.Get("tx_type"), r.PostForm.Get("tx_info"))
+	case "/api/v1/sendTxs":
+		if !l.batchSend {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodPost {
+			err = invalidParam("sendTxs must be posted")
+			break
+		}
+		result, err = l.postTxs(r.PostForm.Get("txs"))
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		writeError(w, err.code, err.message)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+type apiError struct {
+	code    int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func invalidParam(format string, args ...interface{}) *apiError {
+	return &apiError{types.CodeInvalidParam, "invalid param: " + fmt.Sprintf(format, args...)}
+}
+
+func notFound(what string) *apiError {
+	return &apiError{types.CodeNotFound, what + " not found"}
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": code, "message": message})
+}
+
+func isPending(tx *types.EnrichedTx) bool {
+	return tx.TxStatus() == types.TxStatusPending
+}
+
+func parseInt(value, name string) (int64, *apiError) {
+	v, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, invalidParam("%s %q", name, value)
+	}
+	return v, nil
+}
+
+// page returns the items of the page requested by the offset and limit params
+func page[T any](r *http.Request, items []T) ([]T, *apiError) {
+	offset, err := parseInt(r.Form.Get("offset"), "offset")
+	if err != nil {
+		return nil, err
+	}
+	limit, err := parseInt(r.Form.Get("limit"), "limit")
+	if err != nil {
+		return nil, err
+	}
+	if offset < 0 || limit <= 0 || limit > 100 {
+		return nil, invalidParam("offset %d and limit %d", offset, limit)
+	}
+	if offset >= int64(len(items)) {
+		return []T{}, nil
+	}
+	end := offset + limit
+	if end > int64(len(items)) {
+		end = int64(len(items))
+	}
+	return items[offset:end], nil
+}
+
+func (l *Ledger) layer2BasicInfo() *types.Layer2BasicInfo {
+	info := &types.Layer2BasicInfo{TotalTransactionCount: int64(len(l.txs))}
+	for _, block := range l.blocks {
+		if block.CommittedAt > 0 && block.Height > info.BlockCommitted {
+			info.BlockCommitted = block.Height
+		}
+		if block.VerifiedAt > 0 && block.Height > info.BlockVerified {
+			info.BlockVerified = block.Height
+		}
+	}
+	return info
+}
+
+// search returns the data type of the keyword like ZkBNB: 1 for a block height, 2 for an account name,
+// 3 for an account pk and 4 for a tx hash
+func (l *Ledger) search(keyword string) (*types.Search, *apiError) {
+	if height, err := strconv.ParseInt(keyword, 10, 64); err == nil {
+		if _, ok := l.blocks[height]; ok {
+			return &types.Search{DataType: 1}, nil
+		}
+	}
+	for _, account := range l.accounts {
+		if account.Name == keyword {
+			return &types.Search{DataType: 2}, nil
+		}
+		if account.Pk == keyword {
+			return &types.Search{DataType: 3}, nil
+		}
+	}
+	if l.findTx(keyword) != nil {
+		return &types.Search{DataType: 4}, nil
+	}
+	return nil, notFound("data")
+}
+
+func (l *Ledger) findAccount(by, value string) *types.Account {
+	for _, account := range l.accounts {
+		switch by {
+		case "index", "account_index":
+			if strconv.FormatInt(account.Index, 10) == value {
+				return account
+			}
+		case "name", "account_name":
+			if account.Name == value {
+				return account
+			}
+		case "pk", "account_pk":
+			if account.Pk == value {
+				return account
+			}
+		}
+	}
+	return nil
+}
+
+func (l *Ledger) getAccount(by, value string) (*types.Account, *apiError) {
+	switch by {
+	case "index", "name", "pk":
+	default:
+		return nil, invalidParam("by %q", by)
+	}
+	account := l.findAccount(by, value)
+	if account == nil {
+		return nil, notFound("account")
+	}
+	result := *account
+	result.Nonce = l.nextNonce(account.Index)
+	result.Assets = l.accountAssets(account.Index)
+	return &result, nil
+}
+
+func (l *Ledger) sortedAccounts() []*types.Account {
+	accounts := make([]*types.Account, 0, len(l.accounts))
+	for _, account := range l.accounts {
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].Index < accounts[j].Index })
+	return accounts
+}
+
+func (l *Ledger) getAccounts(r *http.Request) (*types.Accounts, *apiError) {
+	accounts, err := page(r, l.sortedAccounts())
+	if err != nil {
+		return nil, err
+	}
+	result := &types.Accounts{Total: uint32(len(l.accounts)), Accounts: []*types.SimpleAccount{}}
+	for _, account := range accounts {
+		result.Accounts = append(result.Accounts, &types.SimpleAccount{Index: account.Index, Name: account.Name, Pk: account.Pk})
+	}
+	return result, nil
+}
+
+func (l *Ledger) sortedAssets() []*types.Asset {
+	assets := make([]*types.Asset, 0, len(l.assets))
+	for _, asset := range l.assets {
+		assets = append(assets, asset)
+	}
+	sort.Slice(assets, func(i, j int) bool { return assets[i].Id < assets[j].Id })
+	return assets
+}
+
+func (l *Ledger) getAsset(by, value string) (*types.Asset, *apiError) {
+	for _, asset := range l.assets {
+		switch by {
+		case "id":
+			if strconv.FormatUint(uint64(asset.Id), 10) == value {
+				return asset, nil
+			}
+		case "symbol":
+			if asset.Symbol == value {
+				return asset, nil
+			}
+		default:
+			return nil, invalidParam("by %q", by)
+		}
+	}
+	return nil, notFound("asset")
+}
+
+func (l *Ledger) getAssets(r *http.Request) (*types.Assets, *apiError) {
+	assets, err := page(r, l.sortedAssets())
+	if err != nil {
+		return nil, err
+	}
+	return &types.Assets{Total: uint32(len(l.assets)), Assets: assets}, nil
+}
+
+func (l *Ledger) getGasFeeAssets() *types.GasFeeAssets {
+	result := &types.GasFeeAssets{Assets: []types.Asset{}}
+	for _, asset := range l.sortedAssets() {
+		if asset.IsGasAsset == 1 {
+			result.Assets = append(result.Assets, *asset)
+		}
+	}
+	return result
+}
+
+func (l *Ledger) getGasFee(r *http.Request) (*types.GasFee, *apiError) {
+	assetId, err := parseInt(r.Form.Get("asset_id"), "asset_id")
+	if err != nil {
+		return nil, err
+	}
+	txType, err := parseInt(r.Form.Get("tx_type"), "tx_type")
+	if err != nil {
+		return nil, err
+	}
+	if asset, ok := l.assets[uint32(assetId)]; !ok || asset.IsGasAsset != 1 {
+		return nil, invalidParam("asset %d is not a gas asset", assetId)
+	}
+	return &types.GasFee{GasFee: l.gasFee(assetId, int(txType)).String()}, nil
+}
+
+func (l *Ledger) getNextNonce(r *http.Request) (*types.NextNonce, *apiError) {
+	accountIndex, err := parseInt(r.Form.Get("account_index"), "account_index")
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := l.accounts[accountIndex]; !ok {
+		return nil, notFound("account")
+	}
+	return &types.NextNonce{Nonce: uint64(l.nextNonce(accountIndex))}, nil
+}
+
+func (l *Ledger) getMaxOfferId(r *http.Request) (*types.MaxOfferId, *apiError) {
+	accountIndex, err := parseInt(r.Form.Get("account_index"), "account_index")
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := l.accounts[accountIndex]; !ok {
+		return nil, notFound("account")
+	}
+	return &types.MaxOfferId{OfferId: l.maxOfferIds[accountIndex]}, nil
+}
+
+func (l *Ledger) getBlock(by, value string) (*types.Block, *apiError) {
+	for _, block := range l.blocks {
+		switch by {
+		case "height":
+			if strconv.FormatInt(block.Height, 10) == value {
+				return block, nil
+			}
+		case "commitment":
+			if block.Commitment == value {
+				return block, nil
+			}
+		default:
+			return nil, invalidParam("by %q", by)
+		}
+	}
+	return nil, notFound("block")
+}
+
+func (l *Ledger) getBlocks(r *http.Request) (*types.Blocks, *apiError) {
+	blocks := make([]*types.Block, 0, len(l.blocks))
+	for _, block := range l.blocks {
+		blocks = append(blocks, block)
+	}
+	// the newest blocks first
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].Height > blocks[j].Height })
+	blocks, err := page(r, blocks)
+	if err != nil {
+		return nil, err
+	}
+	return &types.Blocks{Total: uint32(len(l.blocks)), Blocks: blocks}, nil
+}
+
+func (l *Ledger) getBlockTxs(r *http.Request) (*types.Txs, *apiError) {
+	if by := r.Form.Get("by"); by != "block_height" {
+		return nil, invalidParam("by %q", by)
+	}
+	height, err := parseInt(r.Form.Get("value"), "value")
+	if err != nil {
+		return nil, err
+	}
+	result := &types.Txs{Txs: []*types.Tx{}}
+	for _, tx := range l.txs {
+		if tx.BlockHeight == height && tx.TxStatus() >= types.TxStatusPacked {
+			t := tx.Tx
+			result.Txs = append(result.Txs, &t)
+		}
+	}
+	result.Total = uint32(len(result.Txs))
+	return result, nil
+}
+
+func (l *Ledger) getTx(hash string) (*types.EnrichedTx, *apiError) {
+	tx := l.findTx(hash)
+	if tx == nil {
+		return nil, notFound("tx")
+	}
+	return tx, nil
+}
+
+// listTxs returns the txs matching the filter, the newest first
+func (l *Ledger) listTxs(r *http.Request, filter func(tx *types.EnrichedTx) bool) (*types.Txs, *apiError) {
+	var txTypes []int64
+	if value := r.Form.Get("types"); value != "" {
+		if err := json.Unmarshal([]byte(value), &txTypes); err != nil {
+			return nil, invalidParam("types %q", value)
+		}
+	}
+	var txs []*types.Tx
+	for i := len(l.txs) - 1; i >= 0; i-- {
+		tx := l.txs[i]
+		if !filter(tx) || (len(txTypes) > 0 && !containsType(txTypes, tx.Type)) {
+			continue
+		}
+		t := tx.Tx
+		txs = append(txs, &t)
+	}
+	if r.Form.Get("offset") == "" {
+		return &types.Txs{Total: uint32(len(txs)), Txs: txs}, nil
+	}
+	pageTxs, err := page(r, txs)
+	if err != nil {
+		return nil, err
+	}
+	return &types.Txs{Total: uint32(len(txs)), Txs: pageTxs}, nil
+}
+
+func containsType(txTypes []int64, txType int64) bool {
+	for _, t := range txTypes {
+		if t == txType {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Ledger) getTxs(r *http.Request, filter func(tx *types.EnrichedTx) bool) (*types.Txs, *apiError) {
+	if r.Form.Get("offset") == "" || r.Form.Get("limit") == "" {
+		return nil, invalidParam("offset and limit are required")
+	}
+	return l.listTxs(r, filter)
+}
+
+func (l *Ledger) getAccountTxs(r *http.Request, filter func(tx *types.EnrichedTx) bool) (*types.Txs, *apiError) {
+	account := l.findAccount(r.Form.Get("by"), r.Form.Get("value"))
+	if account == nil {
+		return nil, notFound("account")
+	}
+	return l.listTxs(r, func(tx *types.EnrichedTx) bool {
+		return filter(tx) && (tx.AccountIndex == account.Index || tx.ToAccountIndex == account.Index)
+	})
+}
+
+func (l *Ledger) getAccountNfts(r *http.Request) (*types.Nfts, *apiError) {
+	account := l.findAccount(r.Form.Get("by"), r.Form.Get("value"))
+	if account == nil {
+		return nil, notFound("account")
+	}
+	var nfts []*types.Nft
+	for _, nft := range l.nfts {
+		if nft.OwnerAccountIndex == account.Index {
+			nfts = append(nfts, nft)
+		}
+	}
+	sort.Slice(nfts, func(i, j int) bool { return nfts[i].Index < nfts[j].Index })
+	total := int64(len(nfts))
+	nfts, err := page(r, nfts)
+	if err != nil {
+		return nil, err
+	}
+	return &types.Nfts{Total: total, Nfts: nfts}, nil
+}
+
+func (l *Ledger) postTx(txTypeValue, txInfo string) (*types.TxHash, *apiError) {
+	txType, err := parseInt(txTypeValue, "tx_type")
+	if err != nil {
+		return nil, err
+	}
+	hash, err := l.sendTx(uint32(txType), txInfo)
+	if err != nil {
+		return nil, err
+	}
+	return &types.TxHash{TxHash: hash}, nil
+}
+
+func (l *Ledger) postTxs(txs string) (*types.SendTxResults, *apiError) {
+	var rawTxs []*types.RawTx
+	if err := json.Unmarshal([]byte(txs), &rawTxs); err != nil {
+		return nil, invalidParam("txs: %v", err)
+	}
+	results := &types.SendTxResults{Results: make([]*types.SendTxResult, len(rawTxs))}
+	for i, rawTx := range rawTxs {
+		hash, err := l.sendTx(rawTx.TxType, rawTx.TxInfo)
+		if err != nil {
+			results.Results[i] = &types.SendTxResult{Code: err.code, Message: err.message}
+			continue
+		}
+		results.Results[i] = &types.SendTxResult{TxHash: hash}
+	}
+	return results, nil
+}
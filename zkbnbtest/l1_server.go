@@ -0,0 +1,167 @@
+package zkbnbtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/client/abi"
+)
+
+// L1Tx is a tx received by the fake l1 node, decoded as a call of the ZkBNB contract.
+type L1Tx struct {
+	Tx   *ethtypes.Transaction
+	From common.Address
+	// Method is the name of the called contract method
+	Method string
+	Args   map[string]interface{}
+}
+
+// L1Server is a fake BSC json rpc node which accepts the txs sending to the ZkBNB contract without executing them.
+type L1Server struct {
+	*httptest.Server
+
+	ChainId  *big.Int
+	GasPrice *big.Int
+
+	contractAbi *ethabi.ABI
+	mu          sync.Mutex
+	nonces      map[common.Address]uint64
+	txs         []*L1Tx
+}
+
+// NewL1Server starts a fake l1 node
+func NewL1Server() *L1Server {
+	contractAbi, err := abi.ZkBNBMetaData.GetAbi()
+	if err != nil {
+		panic(err)
+	}
+	s := &L1Server{
+		ChainId:     big.NewInt(97),
+		GasPrice:    big.NewInt(10_000_000_000),
+		contractAbi: contractAbi,
+		nonces:      make(map[common.Address]uint64),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Txs returns the txs sent to the node
+func (s *L1Server) Txs() []*L1Tx {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*L1Tx(nil), s.txs...)
+}
+
+type rpcRequest struct {
+	Id     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	Version string          `json:"jsonrpc"`
+	Id      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+func (s *L1Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	var req rpcRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp := rpcResponse{Version: "2.0", Id: req.Id}
+	result, err := s.call(req.Method, req.Params)
+	if err != nil {
+		resp.Error = &rpcError{Code: -32000, Message: err.Error()}
+	} else {
+		resp.Result = result
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (s *L1Server) call(method string, params []json.RawMessage) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch method {
+	case "eth_chainId":
+		return (*hexutil.Big)(s.ChainId), nil
+	case "eth_gasPrice":
+		return (*hexutil.Big)(s.GasPrice), nil
+	case "eth_getTransactionCount":
+		var address common.Address
+		if err := unmarshalParam(params, 0, &address); err != nil {
+			return nil, err
+		}
+		return hexutil.Uint64(s.nonces[address]), nil
+	case "eth_getCode":
+		// every address is a contract, so that the bindings can send txs to it
+		return hexutil.Bytes{0x1}, nil
+	case "eth_estimateGas":
+		return hexutil.Uint64(300_000), nil
+	case "eth_sendRawTransaction":
+		var raw hexutil.Bytes
+		if err := unmarshalParam(params, 0, &raw); err != nil {
+			return nil, err
+		}
+		tx, err := s.receive(raw)
+		if err != nil {
+			return nil, err
+		}
+		return tx.Hash(), nil
+	}
+	return nil, fmt.Errorf("the method %s does not exist/is not available", method)
+}
+
+// receive decodes a raw tx and checks its chain id and nonce
+func (s *L1Server) receive(raw []byte) (*ethtypes.Transaction, error) {
+	tx := new(ethtypes.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil, err
+	}
+	from, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(s.ChainId), tx)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Nonce() != s.nonces[from] {
+		return nil, fmt.Errorf("invalid nonce %d, expected %d", tx.Nonce(), s.nonces[from])
+	}
+
+	l1Tx := &L1Tx{Tx: tx, From: from, Args: make(map[string]interface{})}
+	if data := tx.Data(); len(data) >= 4 {
+		m, err := s.contractAbi.MethodById(data[:4])
+		if err != nil {
+			return nil, err
+		}
+		if err := m.Inputs.UnpackIntoMap(l1Tx.Args, data[4:]); err != nil {
+			return nil, err
+		}
+		l1Tx.Method = m.Name
+	}
+	s.nonces[from]++
+	s.txs = append(s.txs, l1Tx)
+	return tx, nil
+}
+
+func unmarshalParam(params []json.RawMessage, i int, v interface{}) error {
+	if i >= len(params) {
+		return fmt.Errorf("missing param %d", i)
+	}
+	return json.Unmarshal(params[i], v)
+}
@@ -0,0 +1,703 @@
+package zkbnbtest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/accounts"
+	"github.com/bnb-chain/zkbnb-go-sdk/txutils"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+)
+
+var (
+	// DefaultGasAccount is the gas account of a new ledger
+	DefaultGasAccount = &types.GasAccount{Status: int64(types.AccountStatusConfirmed), Index: 1, Name: "gas.legend"}
+	// DefaultAsset is the gas asset of a new ledger
+	DefaultAsset = &types.Asset{Id: 0, Name: "BNB", Decimals: 18, Symbol: "BNB", Price: "300.00", IsGasAsset: 1}
+	// DefaultGasFee is the gas fee of every tx type unless set with SetGasFee
+	DefaultGasFee = big.NewInt(1000)
+)
+
+// SentTx is a tx received by the sendTx api.
+type SentTx struct {
+	TxType uint32
+	TxInfo string
+	// Hash is the hash of the accepted tx
+	Hash string
+	// Err is the reason the tx was rejected, nil if it was accepted
+	Err error
+}
+
+type gasFeeKey struct {
+	assetId int64
+	txType  int
+}
+
+type balanceKey struct {
+	accountIndex int64
+	assetId      int64
+}
+
+type offerKey struct {
+	accountIndex int64
+	offerId      int64
+}
+
+// Ledger is an in-memory l2 state: accounts with their balances and nonces, nfts, collections and offers.
+// The txs sent to it are validated and applied like ZkBNB does, they are pending until the next Commit.
+// Its fixtures can be changed at any time, even while a client uses it.
+type Ledger struct {
+	mu          sync.Mutex
+	accounts    map[int64]*types.Account
+	balances    map[int64]map[int64]*big.Int
+	assets      map[uint32]*types.Asset
+	gasAccount  *types.GasAccount
+	gasFees     map[gasFeeKey]*big.Int
+	nonces      map[int64]int64
+	collections map[int64]int64
+	usedOffers  map[offerKey]bool
+	maxOfferIds map[int64]uint64
+	blocks      map[int64]*types.Block
+	nfts        map[int64]*types.Nft
+	// txs are all the known txs, from the oldest to the newest
+	txs        []*types.EnrichedTx
+	sentTxs    []*SentTx
+	handlers   map[string]http.HandlerFunc
+	autoCommit bool
+	batchSend  bool
+}
+
+// NewLedger returns an empty ledger with the default gas account and asset.
+func NewLedger() *Ledger {
+	l := &Ledger{
+		accounts:    make(map[int64]*types.Account),
+		balances:    make(map[int64]map[int64]*big.Int),
+		assets:      make(map[uint32]*types.Asset),
+		gasFees:     make(map[gasFeeKey]*big.Int),
+		nonces:      make(map[int64]int64),
+		collections: make(map[int64]int64),
+		usedOffers:  make(map[offerKey]bool),
+		maxOfferIds: make(map[int64]uint64),
+		blocks:      make(map[int64]*types.Block),
+		nfts:        make(map[int64]*types.Nft),
+		handlers:    make(map[string]http.HandlerFunc),
+	}
+	l.SetGasAccount(DefaultGasAccount)
+	l.AddAsset(DefaultAsset)
+	return l
+}
+
+// AddAccount adds or replaces an account, the balances of its assets are set as well.
+// Its nonce is the next nonce of the account unless set with SetNonce.
+func (l *Ledger) AddAccount(account *types.Account) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.accounts[account.Index] = account
+	for _, asset := range account.Assets {
+		if balance, ok := new(big.Int).SetString(asset.Balance, 10); ok {
+			l.setBalance(account.Index, int64(asset.Id), balance)
+		}
+	}
+}
+
+// AddAccountFromSeed adds an account with the key generated from seed and returns the key manager
+func (l *Ledger) AddAccountFromSeed(index int64, name, seed string) (accounts.KeyManager, error) {
+	keyManager, err := accounts.NewSeedKeyManager(seed)
+	if err != nil {
+		return nil, err
+	}
+	l.AddAccount(&types.Account{
+		Status: uint32(types.AccountStatusConfirmed),
+		Index:  index,
+		Name:   name,
+		Pk:     hex.EncodeToString(keyManager.PubKey().Bytes()),
+	})
+	return keyManager, nil
+}
+
+// SetBalance sets the balance of an asset of the account
+func (l *Ledger) SetBalance(accountIndex, assetId int64, balance *big.Int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.setBalance(accountIndex, assetId, balance)
+}
+
+// Balance returns the balance of an asset of the account
+func (l *Ledger) Balance(accountIndex, assetId int64) *big.Int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return new(big.Int).Set(l.balance(accountIndex, assetId))
+}
+
+// AddAsset adds or replaces an asset
+func (l *Ledger) AddAsset(asset *types.Asset) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.assets[asset.Id] = asset
+}
+
+// SetGasAccount sets the gas account, it is also added to the accounts
+func (l *Ledger) SetGasAccount(gasAccount *types.GasAccount) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.gasAccount = gasAccount
+	if _, ok := l.accounts[gasAccount.Index]; !ok {
+		l.accounts[gasAccount.Index] = &types.Account{Status: uint32(gasAccount.Status), Index: gasAccount.Index, Name: gasAccount.Name}
+	}
+}
+
+// SetGasFee sets the gas fee of the tx type paid in the asset
+func (l *Ledger) SetGasFee(assetId int64, txType int, gasFee *big.Int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.gasFees[gasFeeKey{assetId, txType}] = gasFee
+}
+
+// SetNonce sets the next nonce of the account
+func (l *Ledger) SetNonce(accountIndex, nonce int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.nonces[accountIndex] = nonce
+}
+
+// SetMaxOfferId sets the max offer id of the account, the offers up to it are not marked as used
+func (l *Ledger) SetMaxOfferId(accountIndex int64, offerId uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxOfferIds[accountIndex] = offerId
+}
+
+// IsOfferUsed reports whether the offer was matched or canceled
+func (l *Ledger) IsOfferUsed(accountIndex, offerId int64) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.usedOffers[offerKey{accountIndex, offerId}]
+}
+
+// AddCollection creates a collection of the account without a tx and returns its id
+func (l *Ledger) AddCollection(accountIndex int64) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	id := l.collections[accountIndex]
+	l.collections[accountIndex]++
+	return id
+}
+
+// AddNft adds or replaces a nft
+func (l *Ledger) AddNft(nft *types.Nft) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.nfts[nft.Index] = nft
+}
+
+// Nft returns a copy of the nft, nil if it does not exist
+func (l *Ledger) Nft(index int64) *types.Nft {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	nft, ok := l.nfts[index]
+	if !ok {
+		return nil
+	}
+	result := *nft
+	return &result
+}
+
+// AddBlock adds or replaces a block, its txs are added to the txs and the current height is the highest block
+func (l *Ledger) AddBlock(block *types.Block) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.blocks[block.Height] = block
+	for _, tx := range block.Txs {
+		tx.BlockHeight = block.Height
+		if existing := l.findTx(tx.Hash); existing != nil {
+			existing.Tx = *tx
+			continue
+		}
+		l.txs = append(l.txs, &types.EnrichedTx{Tx: *tx})
+	}
+}
+
+// SetTxStatus changes the status of a known tx
+func (l *Ledger) SetTxStatus(hash string, status types.TxStatus) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if tx := l.findTx(hash); tx != nil {
+		tx.Status = int64(status)
+	}
+}
+
+// SetAutoCommit makes every accepted tx committed in its own block right away
+func (l *Ledger) SetAutoCommit(autoCommit bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.autoCommit = autoCommit
+}
+
+// SetBatchSend enables the sendTxs api, which applies several txs in order, for the clients created with
+// WithBatchEndpoint. ZkBNB has no such api, it is disabled by default.
+func (l *Ledger) SetBatchSend(batchSend bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.batchSend = batchSend
+}
+
+// Commit packs the pending txs in a new verified block and returns it, nil if there is no pending tx
+func (l *Ledger) Commit() *types.Block {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.commit()
+}
+
+// Handle overrides the handler of an api path, e.g. to make it fail
+func (l *Ledger) Handle(path string, handler http.HandlerFunc) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.handlers[path] = handler
+}
+
+// SentTxs returns the txs received by the sendTx and sendTxs apis, including the rejected ones
+func (l *Ledger) SentTxs() []*SentTx {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]*SentTx(nil), l.sentTxs...)
+}
+
+// AcceptedTxs returns the txs accepted by the sendTx and sendTxs apis
+func (l *Ledger) AcceptedTxs() []*SentTx {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var accepted []*SentTx
+	for _, tx := range l.sentTxs {
+		if tx.Err == nil {
+			accepted = append(accepted, tx)
+		}
+	}
+	return accepted
+}
+
+// SendTx validates the tx and applies it, it is what the sendTx api does
+func (l *Ledger) SendTx(txType uint32, txInfo string) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	hash, err := l.sendTx(txType, txInfo)
+	if err != nil {
+		return "", err
+	}
+	return hash, nil
+}
+
+func (l *Ledger) setBalance(accountIndex, assetId int64, balance *big.Int) {
+	if l.balances[accountIndex] == nil {
+		l.balances[accountIndex] = make(map[int64]*big.Int)
+	}
+	l.balances[accountIndex][assetId] = new(big.Int).Set(balance)
+}
+
+func (l *Ledger) balance(accountIndex, assetId int64) *big.Int {
+	if balance, ok := l.balances[accountIndex][assetId]; ok {
+		return balance
+	}
+	return new(big.Int)
+}
+
+// accountAssets returns the assets of the account with a balance
+func (l *Ledger) accountAssets(accountIndex int64) []*types.AccountAsset {
+	var assets []*types.AccountAsset
+	for assetId, balance := range l.balances[accountIndex] {
+		asset := &types.AccountAsset{Id: uint32(assetId), Balance: balance.String()}
+		if a, ok := l.assets[uint32(assetId)]; ok {
+			asset.Name = a.Name
+			asset.Price = a.Price
+		}
+		assets = append(assets, asset)
+	}
+	sort.Slice(assets, func(i, j int) bool { return assets[i].Id < assets[j].Id })
+	return assets
+}
+
+func (l *Ledger) nextNonce(accountIndex int64) int64 {
+	if nonce, ok := l.nonces[accountIndex]; ok {
+		return nonce
+	}
+	if account, ok := l.accounts[accountIndex]; ok {
+		return account.Nonce
+	}
+	return 0
+}
+
+func (l *Ledger) gasFee(assetId int64, txType int) *big.Int {
+	if gasFee, ok := l.gasFees[gasFeeKey{assetId, txType}]; ok {
+		return gasFee
+	}
+	return DefaultGasFee
+}
+
+func (l *Ledger) currentHeight() int64 {
+	var height int64
+	for h := range l.blocks {
+		if h > height {
+			height = h
+		}
+	}
+	return height
+}
+
+func (l *Ledger) findTx(hash string) *types.EnrichedTx {
+	for _, tx := range l.txs {
+		if tx.Hash == hash {
+			return tx
+		}
+	}
+	return nil
+}
+
+func (l *Ledger) commit() *types.Block {
+	now := time.Now()
+	block := &types.Block{
+		Height:      l.currentHeight() + 1,
+		Status:      int64(types.BlockStatusVerified),
+		CommittedAt: now.UnixMilli(),
+		VerifiedAt:  now.UnixMilli(),
+	}
+	digest := sha256.New()
+	for _, tx := range l.txs {
+		if tx.TxStatus() != types.TxStatusPending {
+			continue
+		}
+		tx.Status = int64(types.TxStatusVerified)
+		tx.BlockHeight = block.Height
+		tx.ExecutedAt = now.Unix()
+		tx.CommittedAt = now.Unix()
+		tx.VerifiedAt = now.Unix()
+		t := tx.Tx
+		block.Txs = append(block.Txs, &t)
+		digest.Write([]byte(tx.Hash))
+	}
+	if len(block.Txs) == 0 {
+		return nil
+	}
+	block.Size = uint16(len(block.Txs))
+	block.Commitment = hex.EncodeToString(digest.Sum(nil))
+	block.CommittedTxHash = "0x" + block.Commitment
+	block.VerifiedTxHash = "0x" + block.Commitment
+	l.blocks[block.Height] = block
+	return block
+}
+
+func (l *Ledger) sendTx(txType uint32, txInfo string) (string, *apiError) {
+	sent := &SentTx{TxType: txType, TxInfo: txInfo}
+	l.sentTxs = append(l.sentTxs, sent)
+
+	hash, err := l.applyTx(txType, txInfo)
+	if err != nil {
+		sent.Err = err
+		return "", err
+	}
+	sent.Hash = hash
+	if l.autoCommit {
+		l.commit()
+	}
+	return hash, nil
+}
+
+// applyTx checks the tx like ZkBNB does, then applies it to the state and records it as pending
+func (l *Ledger) applyTx(txType uint32, txInfo string) (string, *apiError) {
+	parsed, err := txutils.ParseTxInfo(txType, txInfo)
+	if err != nil {
+		return "", invalidParam("%v", err)
+	}
+	if err := parsed.Validate(); err != nil {
+		return "", &apiError{types.CodeInvalidTxField, "invalid tx field: " + err.Error()}
+	}
+	if parsed.GetExpiredAt() < time.Now().UnixMilli() {
+		return "", &apiError{types.CodeInvalidExpireTime, "invalid expired time"}
+	}
+
+	accountIndex := parsed.GetFromAccountIndex()
+	account, ok := l.accounts[accountIndex]
+	if !ok {
+		return "", notFound("account")
+	}
+
+	var offerPks []string
+	if atomicMatch, ok := parsed.(*types.AtomicMatchTxInfo); ok {
+		for _, offer := range []int64{atomicMatch.BuyOffer.AccountIndex, atomicMatch.SellOffer.AccountIndex} {
+			owner, ok := l.accounts[offer]
+			if !ok {
+				return "", notFound("account")
+			}
+			offerPks = append(offerPks, owner.Pk)
+		}
+	}
+	if err := txutils.VerifyTxSignature(txType, txInfo, account.Pk, offerPks...); err != nil {
+		return "", &apiError{types.CodeInvalidTxField, "invalid signature"}
+	}
+
+	if nonce := l.nextNonce(accountIndex); parsed.GetNonce() != nonce {
+		return "", &apiError{types.CodeInvalidNonce, fmt.Sprintf("invalid nonce, expected %d", nonce)}
+	}
+
+	gasAccountIndex, gasFeeAssetId, gasFeeAmount := parsed.GetGas()
+	if gasAccountIndex != l.gasAccount.Index {
+		return "", &apiError{types.CodeInvalidGasFee, fmt.Sprintf("invalid gas fee, gas account is %d", l.gasAccount.Index)}
+	}
+	if asset, ok := l.assets[uint32(gasFeeAssetId)]; !ok || asset.IsGasAsset != 1 {
+		return "", &apiError{types.CodeInvalidGasFee, fmt.Sprintf("invalid gas fee, asset %d is not a gas asset", gasFeeAssetId)}
+	}
+	if gasFeeAmount == nil || gasFeeAmount.Cmp(l.gasFee(gasFeeAssetId, int(txType))) < 0 {
+		return "", &apiError{types.CodeInvalidGasFee, "gas fee too low"}
+	}
+
+	hash, err := txutils.TxHash(txType, txInfo)
+	if err != nil {
+		return "", &apiError{types.CodeInvalidTxField, "invalid tx field: " + err.Error()}
+	}
+	tx := &types.EnrichedTx{Tx: types.Tx{
+		Hash:          hash,
+		Type:          int64(txType),
+		Info:          txInfo,
+		Status:        int64(types.TxStatusPending),
+		AccountIndex:  accountIndex,
+		AccountName:   account.Name,
+		Nonce:         parsed.GetNonce(),
+		ExpiredAt:     parsed.GetExpiredAt(),
+		GasFeeAssetId: gasFeeAssetId,
+		GasFee:        gasFeeAmount.String(),
+		CreatedAt:     time.Now().Unix(),
+	}}
+
+	// the balance moves of the tx, the gas fee first
+	moves := []*move{{accountIndex, gasAccountIndex, gasFeeAssetId, gasFeeAmount}}
+	var apply func()
+	switch parsed := parsed.(type) {
+	case *types.TransferTxInfo:
+		apiErr := l.checkReceiver(parsed.ToAccountIndex, parsed.ToAccountNameHash, tx)
+		if apiErr != nil {
+			return "", apiErr
+		}
+		moves = append(moves, &move{accountIndex, parsed.ToAccountIndex, parsed.AssetId, parsed.AssetAmount})
+		tx.AssetId, tx.Amount, tx.Memo = parsed.AssetId, parsed.AssetAmount.String(), parsed.Memo
+	case *types.WithdrawTxInfo:
+		moves = append(moves, &move{accountIndex, -1, parsed.AssetId, parsed.AssetAmount})
+		tx.AssetId, tx.Amount, tx.NativeAddress = parsed.AssetId, parsed.AssetAmount.String(), parsed.ToAddress
+	case *types.CreateCollectionTxInfo:
+		apply = func() {
+			tx.CollectionId = l.collections[accountIndex]
+			l.collections[accountIndex]++
+		}
+	case *types.MintNftTxInfo:
+		apiErr := l.checkReceiver(parsed.ToAccountIndex, parsed.ToAccountNameHash, tx)
+		if apiErr != nil {
+			return "", apiErr
+		}
+		if parsed.NftCollectionId >= l.collections[accountIndex] {
+			return "", notFound("collection")
+		}
+		apply = func() {
+			tx.NftIndex = l.nextNftIndex()
+			tx.CollectionId = parsed.NftCollectionId
+			l.nfts[tx.NftIndex] = &types.Nft{
+				Index:               tx.NftIndex,
+				CreatorAccountIndex: accountIndex,
+				CreatorAccountName:  account.Name,
+				OwnerAccountIndex:   parsed.ToAccountIndex,
+				OwnerAccountName:    tx.ToAccountName,
+				ContentHash:         parsed.NftContentHash,
+				CreatorTreasuryRate: parsed.CreatorTreasuryRate,
+				CollectionId:        parsed.NftCollectionId,
+			}
+		}
+	case *types.TransferNftTxInfo:
+		apiErr := l.checkReceiver(parsed.ToAccountIndex, parsed.ToAccountNameHash, tx)
+		if apiErr != nil {
+			return "", apiErr
+		}
+		nft, apiErr := l.ownedNft(parsed.NftIndex, accountIndex)
+		if apiErr != nil {
+			return "", apiErr
+		}
+		tx.NftIndex = nft.Index
+		apply = func() {
+			nft.OwnerAccountIndex = parsed.ToAccountIndex
+			nft.OwnerAccountName = tx.ToAccountName
+		}
+	case *types.WithdrawNftTxInfo:
+		nft, apiErr := l.ownedNft(parsed.NftIndex, accountIndex)
+		if apiErr != nil {
+			return "", apiErr
+		}
+		tx.NftIndex, tx.NativeAddress = nft.Index, parsed.ToAddress
+		apply = func() {
+			delete(l.nfts, nft.Index)
+		}
+	case *types.AtomicMatchTxInfo:
+		matchMoves, nft, apiErr := l.matchOffers(parsed)
+		if apiErr != nil {
+			return "", apiErr
+		}
+		moves = append(moves, matchMoves...)
+		buyer := l.accounts[parsed.BuyOffer.AccountIndex]
+		tx.NftIndex, tx.AssetId, tx.Amount = nft.Index, parsed.BuyOffer.AssetId, parsed.BuyOffer.AssetAmount.String()
+		tx.ToAccountIndex, tx.ToAccountName = buyer.Index, buyer.Name
+		apply = func() {
+			l.useOffer(parsed.BuyOffer.AccountIndex, parsed.BuyOffer.OfferId)
+			l.useOffer(parsed.SellOffer.AccountIndex, parsed.SellOffer.OfferId)
+			nft.OwnerAccountIndex = buyer.Index
+			nft.OwnerAccountName = buyer.Name
+		}
+	case *types.CancelOfferTxInfo:
+		if l.usedOffers[offerKey{accountIndex, parsed.OfferId}] {
+			return "", &apiError{types.CodeInvalidTxField, "offer id is already used"}
+		}
+		apply = func() {
+			l.useOffer(accountIndex, parsed.OfferId)
+		}
+	}
+
+	if apiErr := l.applyMoves(moves); apiErr != nil {
+		return "", apiErr
+	}
+	if apply != nil {
+		apply()
+	}
+	l.nonces[accountIndex] = parsed.GetNonce() + 1
+	l.txs = append(l.txs, tx)
+	return hash, nil
+}
+
+// move is a transfer of an asset between two accounts, to is -1 when the asset leaves l2
+type move struct {
+	from    int64
+	to      int64
+	assetId int64
+	amount  *big.Int
+}
+
+// applyMoves applies all the moves, or none of them if an account can't pay them
+func (l *Ledger) applyMoves(moves []*move) *apiError {
+	debits := make(map[balanceKey]*big.Int)
+	for _, m := range moves {
+		key := balanceKey{m.from, m.assetId}
+		if debits[key] == nil {
+			debits[key] = new(big.Int)
+		}
+		debits[key].Add(debits[key], m.amount)
+	}
+	for key, debit := range debits {
+		if l.balance(key.accountIndex, key.assetId).Cmp(debit) < 0 {
+			return &apiError{types.CodeBalanceNotEnough, fmt.Sprintf("balance is not enough, account %d asset %d", key.accountIndex, key.assetId)}
+		}
+	}
+	for _, m := range moves {
+		l.setBalance(m.from, m.assetId, new(big.Int).Sub(l.balance(m.from, m.assetId), m.amount))
+		if m.to >= 0 {
+			l.setBalance(m.to, m.assetId, new(big.Int).Add(l.balance(m.to, m.assetId), m.amount))
+		}
+	}
+	return nil
+}
+
+// checkReceiver checks the receiver of the tx exists and matches the name hash signed in the tx
+func (l *Ledger) checkReceiver(toAccountIndex int64, toAccountNameHash string, tx *types.EnrichedTx) *apiError {
+	to, ok := l.accounts[toAccountIndex]
+	if !ok {
+		return notFound("account")
+	}
+	nameHash, err := txutils.AccountNameHash(to.Name)
+	if err != nil || nameHash != toAccountNameHash {
+		return &apiError{types.CodeInvalidTxField, "invalid tx field: to account name hash does not match the to account index"}
+	}
+	tx.ToAccountIndex = to.Index
+	tx.ToAccountName = to.Name
+	return nil
+}
+
+func (l *Ledger) ownedNft(nftIndex, accountIndex int64) (*types.Nft, *apiError) {
+	nft, ok := l.nfts[nftIndex]
+	if !ok {
+		return nil, notFound("nft")
+	}
+	if nft.OwnerAccountIndex != accountIndex {
+		return nil, &apiError{types.CodeInvalidTxField, fmt.Sprintf("account %d is not owner of the nft %d", accountIndex, nftIndex)}
+	}
+	return nft, nil
+}
+
+func (l *Ledger) nextNftIndex() int64 {
+	var index int64
+	for i := range l.nfts {
+		if i >= index {
+			index = i + 1
+		}
+	}
+	return index
+}
+
+func (l *Ledger) useOffer(accountIndex, offerId int64) {
+	l.usedOffers[offerKey{accountIndex, offerId}] = true
+	if uint64(offerId) > l.maxOfferIds[accountIndex] {
+		l.maxOfferIds[accountIndex] = uint64(offerId)
+	}
+}
+
+// matchOffers checks the offers of an atomic match and returns the payments of the buyer: the treasury fee to the
+// gas account, the royalty to the creator of the nft and the rest to the seller
+func (l *Ledger) matchOffers(tx *types.AtomicMatchTxInfo) ([]*move, *types.Nft, *apiError) {
+	buy, sell := tx.BuyOffer, tx.SellOffer
+	if buy.Type != types.BuyOfferType || sell.Type != types.SellOfferType {
+		return nil, nil, &apiError{types.CodeInvalidTxField, "invalid tx field: invalid offer types"}
+	}
+	if buy.NftIndex != sell.NftIndex || buy.AssetId != sell.AssetId || buy.AssetAmount.Cmp(sell.AssetAmount) != 0 {
+		return nil, nil, &apiError{types.CodeInvalidTxField, "invalid tx field: the buy offer does not match the sell offer"}
+	}
+	now := time.Now().UnixMilli()
+	if buy.ExpiredAt < now || sell.ExpiredAt < now {
+		return nil, nil, &apiError{types.CodeInvalidExpireTime, "invalid expired time of the offer"}
+	}
+	for _, offer := range []offerKey{{buy.AccountIndex, buy.OfferId}, {sell.AccountIndex, sell.OfferId}} {
+		if l.usedOffers[offer] {
+			return nil, nil, &apiError{types.CodeInvalidTxField, fmt.Sprintf("offer id is already used: offer %d of account %d", offer.offerId, offer.accountIndex)}
+		}
+	}
+	nft, apiErr := l.ownedNft(sell.NftIndex, sell.AccountIndex)
+	if apiErr != nil {
+		return nil, nil, apiErr
+	}
+
+	amount := buy.AssetAmount
+	treasuryFee := rate(amount, sell.TreasuryRate)
+	creatorFee := rate(amount, nft.CreatorTreasuryRate)
+	sellerAmount := new(big.Int).Sub(amount, treasuryFee)
+	sellerAmount.Sub(sellerAmount, creatorFee)
+	return []*move{
+		{buy.AccountIndex, l.gasAccount.Index, buy.AssetId, treasuryFee},
+		{buy.AccountIndex, nft.CreatorAccountIndex, buy.AssetId, creatorFee},
+		{buy.AccountIndex, sell.AccountIndex, buy.AssetId, sellerAmount},
+	}, nft, nil
+}
+
+// rate returns the part of the amount for a rate in basis points
+func rate(amount *big.Int, rate int64) *big.Int {
+	part := new(big.Int).Mul(amount, big.NewInt(rate))
+	return part.Div(part, big.NewInt(10000))
+}
+
+// RoundTrip serves the request with the api of the ledger in memory, so that a client can use the ledger as
+// its transport without a server.
+func (l *Ledger) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	recorder := httptest.NewRecorder()
+	l.ServeHTTP(recorder, req)
+	resp := recorder.Result()
+	resp.Request = req
+	return resp, nil
+}
@@ -0,0 +1,26 @@
+// Package zkbnbtest provides an in-memory ZkBNB ledger and fakes of the ZkBNB api server and of the l1 node
+// for testing the clients offline.
+package zkbnbtest
+
+import (
+	"net/http/httptest"
+)
+
+// Server is a fake ZkBNB api server backed by a ledger, the fixtures of the ledger are promoted.
+type Server struct {
+	*httptest.Server
+	*Ledger
+}
+
+// NewServer starts a fake server with a new ledger.
+func NewServer() *Server {
+	return NewLedgerServer(NewLedger())
+}
+
+// NewLedgerServer starts a fake server serving the api of the ledger.
+func NewLedgerServer(ledger *Ledger) *Server {
+	return &Server{
+		Server: httptest.NewServer(ledger),
+		Ledger: ledger,
+	}
+}
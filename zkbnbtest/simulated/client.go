@@ -0,0 +1,23 @@
+// Package simulated provides a ZkBNBClient backed by an in-memory ledger, to test applications deterministically.
+package simulated
+
+import (
+	"github.com/bnb-chain/zkbnb-go-sdk/accounts"
+	"github.com/bnb-chain/zkbnb-go-sdk/client"
+	"github.com/bnb-chain/zkbnb-go-sdk/zkbnbtest"
+)
+
+// Endpoint is the endpoint of the simulated clients, their requests never leave the process
+const Endpoint = "http://simulated.zkbnb"
+
+// NewClient returns a client of the ledger signing the txs with keyManager, keyManager can be nil for a
+// client which only queries. The txs sent are applied to the ledger right away and are pending until the
+// ledger is committed.
+func NewClient(ledger *zkbnbtest.Ledger, keyManager accounts.KeyManager, options ...client.ClientOptionFunc) client.ZkBNBClient {
+	options = append([]client.ClientOptionFunc{client.WithRoundTripper(ledger)}, options...)
+	c := client.NewZkBNBClient(Endpoint, options...)
+	if keyManager != nil {
+		c.SetKeyManager(keyManager)
+	}
+	return c
+}
@@ -0,0 +1,241 @@
+package simulated
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/zkbnb-go-sdk/client"
+	"github.com/bnb-chain/zkbnb-go-sdk/txutils"
+	"github.com/bnb-chain/zkbnb-go-sdk/types"
+	"github.com/bnb-chain/zkbnb-go-sdk/zkbnbtest"
+)
+
+const (
+	sellerSeed = "28e1a3762ff9944e9a4ad79477b756ef0aff3d2af76f0f40a0c3ec6ca76cf24b"
+	buyerSeed  = "17673b9a9fdec6dc90c7cc1eb1c939134dfb659d2f08edbe071e5c45f343d008"
+
+	gasAccount = 1
+	seller     = 2
+	buyer      = 3
+	creator    = 4
+)
+
+var (
+	gasFee  = zkbnbtest.DefaultGasFee
+	balance = big.NewInt(1e18)
+)
+
+// newMarket returns a ledger where the seller owns the nft 0 created by the creator with a 5% royalty,
+// and the clients of the seller and of the buyer.
+func newMarket(t *testing.T) (ledger *zkbnbtest.Ledger, sellerClient, buyerClient client.ZkBNBClient) {
+	ledger = zkbnbtest.NewLedger()
+	sellerKey, err := ledger.AddAccountFromSeed(seller, "sher.legend", sellerSeed)
+	require.NoError(t, err)
+	buyerKey, err := ledger.AddAccountFromSeed(buyer, "gavin.legend", buyerSeed)
+	require.NoError(t, err)
+	ledger.AddAccount(&types.Account{Status: uint32(types.AccountStatusConfirmed), Index: creator, Name: "creator.legend"})
+	ledger.SetBalance(seller, 0, balance)
+	ledger.SetBalance(buyer, 0, balance)
+	ledger.AddNft(&types.Nft{Index: 0, CreatorAccountIndex: creator, OwnerAccountIndex: seller, CreatorTreasuryRate: 500})
+	return ledger, NewClient(ledger, sellerKey), NewClient(ledger, buyerKey)
+}
+
+func sub(x *big.Int, ys ...*big.Int) *big.Int {
+	result := new(big.Int).Set(x)
+	for _, y := range ys {
+		result.Sub(result, y)
+	}
+	return result
+}
+
+func TestTransfer(t *testing.T) {
+	ledger, sellerClient, _ := newMarket(t)
+
+	_, err := sellerClient.Transfer(&types.TransferTxReq{ToAccountName: "gavin.legend", AssetAmount: big.NewInt(100)}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, sub(balance, big.NewInt(100), gasFee), ledger.Balance(seller, 0))
+	assert.Equal(t, new(big.Int).Add(balance, big.NewInt(100)), ledger.Balance(buyer, 0))
+	assert.Equal(t, gasFee, ledger.Balance(gasAccount, 0))
+
+	account, err := sellerClient.GetAccountByIndex(seller)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), account.Nonce)
+	if assert.Len(t, account.Assets, 1) {
+		assert.Equal(t, sub(balance, big.NewInt(100), gasFee).String(), account.Assets[0].Balance)
+	}
+}
+
+func TestTransferValidation(t *testing.T) {
+	ledger, sellerClient, _ := newMarket(t)
+	transfer := &types.TransferTxReq{ToAccountName: "gavin.legend", AssetAmount: big.NewInt(100)}
+
+	_, err := sellerClient.Transfer(&types.TransferTxReq{ToAccountName: "gavin.legend", AssetAmount: balance}, nil)
+	assert.ErrorIs(t, err, client.ErrInsufficientBalance)
+
+	_, err = sellerClient.Transfer(transfer, &types.TransactOpts{Nonce: 5})
+	assert.ErrorIs(t, err, client.ErrInvalidNonce)
+
+	_, err = sellerClient.Transfer(transfer, &types.TransactOpts{ExpiredAt: time.Now().Add(-time.Second).UnixMilli()})
+	assert.ErrorIs(t, err, client.ErrInvalidExpireTime)
+
+	_, err = sellerClient.Transfer(transfer, &types.TransactOpts{GasFeeAssetAmount: big.NewInt(1)})
+	assert.ErrorIs(t, err, client.ErrInvalidGasFee)
+
+	// the rejected txs change nothing
+	assert.Equal(t, balance, ledger.Balance(seller, 0))
+	assert.Equal(t, balance, ledger.Balance(buyer, 0))
+	assert.Empty(t, ledger.AcceptedTxs())
+	assert.Len(t, ledger.SentTxs(), 4)
+}
+
+func TestWithdraw(t *testing.T) {
+	ledger, sellerClient, _ := newMarket(t)
+
+	_, err := sellerClient.Withdraw(&types.WithdrawReq{AssetAmount: big.NewInt(100), ToAddress: "0x8b2C5A5744F42AA9269BaabDd05933a96D8EF911"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, sub(balance, big.NewInt(100), gasFee), ledger.Balance(seller, 0))
+}
+
+func TestMintAndTransferNft(t *testing.T) {
+	ledger, sellerClient, buyerClient := newMarket(t)
+	contentHash := txutils.NftContentHash("content")
+
+	_, err := sellerClient.MintNft(&types.MintNftTxReq{To: "sher.legend", NftContentHash: contentHash}, nil)
+	assert.ErrorIs(t, err, client.ErrNotFound, "the collection does not exist")
+
+	_, err = sellerClient.CreateCollection(&types.CreateCollectionReq{Name: "collection", Introduction: "intro"}, nil)
+	require.NoError(t, err)
+	_, err = sellerClient.MintNft(&types.MintNftTxReq{To: "sher.legend", NftContentHash: contentHash, CreatorTreasuryRate: 100}, nil)
+	require.NoError(t, err)
+
+	nft := ledger.Nft(1)
+	require.NotNil(t, nft)
+	assert.Equal(t, int64(seller), nft.CreatorAccountIndex)
+	assert.Equal(t, int64(seller), nft.OwnerAccountIndex)
+
+	_, err = buyerClient.TransferNft(&types.TransferNftTxReq{NftIndex: 1, To: "gavin.legend"}, nil)
+	assert.ErrorIs(t, err, client.ErrNftNotOwned)
+
+	_, err = sellerClient.TransferNft(&types.TransferNftTxReq{NftIndex: 1, To: "gavin.legend"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(buyer), ledger.Nft(1).OwnerAccountIndex)
+
+	nfts, err := buyerClient.GetNftsByAccountIndex(buyer, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), nfts.Total)
+	assert.Equal(t, sub(balance, gasFee, gasFee, gasFee), ledger.Balance(seller, 0))
+}
+
+func TestAtomicMatch(t *testing.T) {
+	ledger, sellerClient, buyerClient := newMarket(t)
+	price := big.NewInt(10000)
+
+	sellOffer, err := sellerClient.CreateOffer(&types.OfferReq{Type: types.SellOfferType, NftIndex: 0, AssetAmount: price})
+	require.NoError(t, err)
+	buyOffer, err := buyerClient.CreateOffer(&types.OfferReq{Type: types.BuyOfferType, NftIndex: 0, AssetAmount: price})
+	require.NoError(t, err)
+
+	match := &types.AtomicMatchTxReq{BuyOffer: buyOffer, SellOffer: sellOffer}
+	_, err = sellerClient.AtomicMatch(match, nil)
+	require.NoError(t, err)
+
+	// the treasury rate of the offer goes to the gas account, the royalty to the creator
+	treasuryFee := big.NewInt(10000 * 200 / 10000)
+	royalty := big.NewInt(10000 * 500 / 10000)
+	assert.Equal(t, int64(buyer), ledger.Nft(0).OwnerAccountIndex)
+	assert.Equal(t, sub(balance, price), ledger.Balance(buyer, 0))
+	assert.Equal(t, sub(new(big.Int).Add(balance, price), treasuryFee, royalty, gasFee), ledger.Balance(seller, 0))
+	assert.Equal(t, new(big.Int).Add(gasFee, treasuryFee), ledger.Balance(gasAccount, 0))
+	assert.Equal(t, royalty, ledger.Balance(creator, 0))
+	assert.True(t, ledger.IsOfferUsed(buyer, buyOffer.OfferId))
+	assert.True(t, ledger.IsOfferUsed(seller, sellOffer.OfferId))
+
+	_, err = sellerClient.AtomicMatch(match, nil)
+	assert.ErrorIs(t, err, client.ErrOfferUsed)
+
+	offerId, err := buyerClient.GetMaxOfferId(buyer)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(buyOffer.OfferId), offerId)
+}
+
+func TestAtomicMatchNftNotOwned(t *testing.T) {
+	_, sellerClient, buyerClient := newMarket(t)
+	price := big.NewInt(10000)
+
+	// the buyer can't sell the nft of the seller
+	sellOffer, err := buyerClient.CreateOffer(&types.OfferReq{Type: types.SellOfferType, NftIndex: 0, AssetAmount: price})
+	require.NoError(t, err)
+	buyOffer, err := sellerClient.CreateOffer(&types.OfferReq{Type: types.BuyOfferType, NftIndex: 0, AssetAmount: price})
+	require.NoError(t, err)
+
+	_, err = sellerClient.AtomicMatch(&types.AtomicMatchTxReq{BuyOffer: buyOffer, SellOffer: sellOffer}, nil)
+	assert.ErrorIs(t, err, client.ErrNftNotOwned)
+}
+
+func TestCancelOffer(t *testing.T) {
+	ledger, sellerClient, buyerClient := newMarket(t)
+	price := big.NewInt(10000)
+
+	sellOffer, err := sellerClient.CreateOffer(&types.OfferReq{Type: types.SellOfferType, NftIndex: 0, AssetAmount: price})
+	require.NoError(t, err)
+	_, err = sellerClient.CancelOffer(&types.CancelOfferReq{OfferId: sellOffer.OfferId}, nil)
+	require.NoError(t, err)
+	assert.True(t, ledger.IsOfferUsed(seller, sellOffer.OfferId))
+
+	_, err = sellerClient.CancelOffer(&types.CancelOfferReq{OfferId: sellOffer.OfferId}, nil)
+	assert.ErrorIs(t, err, client.ErrOfferUsed)
+
+	buyOffer, err := buyerClient.CreateOffer(&types.OfferReq{Type: types.BuyOfferType, NftIndex: 0, AssetAmount: price})
+	require.NoError(t, err)
+	_, err = sellerClient.AtomicMatch(&types.AtomicMatchTxReq{BuyOffer: buyOffer, SellOffer: sellOffer}, nil)
+	assert.ErrorIs(t, err, client.ErrOfferUsed)
+	assert.Equal(t, int64(seller), ledger.Nft(0).OwnerAccountIndex)
+}
+
+func TestCommit(t *testing.T) {
+	ledger, sellerClient, _ := newMarket(t)
+
+	hash, err := sellerClient.Transfer(&types.TransferTxReq{ToAccountName: "gavin.legend", AssetAmount: big.NewInt(100)}, nil)
+	require.NoError(t, err)
+	tx, err := sellerClient.GetTx(hash)
+	require.NoError(t, err)
+	assert.Equal(t, types.TxStatusPending, tx.TxStatus())
+
+	block := ledger.Commit()
+	require.NotNil(t, block)
+	assert.Equal(t, int64(1), block.Height)
+	assert.Nil(t, ledger.Commit(), "no pending tx")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	tx, err = sellerClient.WaitForTx(ctx, hash, types.TxStatusVerified, client.WaitWithPollInterval(time.Millisecond, time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), tx.BlockHeight)
+
+	txs, err := sellerClient.GetTxsByBlockHeight(1)
+	require.NoError(t, err)
+	if assert.Len(t, txs, 1) {
+		assert.Equal(t, hash, txs[0].Hash)
+	}
+}
+
+func TestAutoCommit(t *testing.T) {
+	ledger, sellerClient, _ := newMarket(t)
+	ledger.SetAutoCommit(true)
+
+	hash, err := sellerClient.Transfer(&types.TransferTxReq{ToAccountName: "gavin.legend", AssetAmount: big.NewInt(100)}, nil)
+	require.NoError(t, err)
+	tx, err := sellerClient.GetTx(hash)
+	require.NoError(t, err)
+	assert.Equal(t, types.TxStatusVerified, tx.TxStatus())
+
+	height, err := sellerClient.GetCurrentHeight()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), height)
+}
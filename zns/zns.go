@@ -0,0 +1,74 @@
+// Package zns validates and normalizes the ZNS account names of ZkBNB, e.g. "sher.legend". A name is a label of
+// lowercase letters and digits followed by the ".legend" suffix, the label alone is registered on L1.
+package zns
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// Suffix is the suffix of every account name, it is the base node of the names
+	Suffix = ".legend"
+	// MinLabelLength is the min length of the label of a name, i.e. without the suffix
+	MinLabelLength = 1
+	// MaxLabelLength is the max length of the label of a name, i.e. without the suffix
+	MaxLabelLength = 20
+)
+
+var (
+	// ErrInvalidLength is returned for a label which is empty or longer than MaxLabelLength
+	ErrInvalidLength = errors.New("invalid account name length")
+	// ErrInvalidCharacter is returned for a label with a character other than a lowercase letter or a digit
+	ErrInvalidCharacter = errors.New("invalid account name character")
+	// ErrInvalidSuffix is returned for a name with a suffix other than Suffix
+	ErrInvalidSuffix = errors.New("invalid account name suffix")
+)
+
+// Validate checks that the name is a normalized account name, e.g. "sher.legend"
+func Validate(name string) error {
+	if !strings.HasSuffix(name, Suffix) {
+		return fmt.Errorf("%w: %q does not end with %q", ErrInvalidSuffix, name, Suffix)
+	}
+	return ValidateLabel(strings.TrimSuffix(name, Suffix))
+}
+
+// ValidateLabel checks that the label is a normalized account name without the suffix, e.g. "sher"
+func ValidateLabel(label string) error {
+	if n := utf8.RuneCountInString(label); n < MinLabelLength || n > MaxLabelLength {
+		return fmt.Errorf("%w: %q has %d characters, expected %d to %d", ErrInvalidLength, label, n, MinLabelLength, MaxLabelLength)
+	}
+	for i, c := range label {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') {
+			return fmt.Errorf("%w: %q at %d of %q, only lowercase letters and digits are allowed", ErrInvalidCharacter, c, i, label)
+		}
+	}
+	return nil
+}
+
+// Normalize returns the account name in lowercase with the suffix, e.g. "Sher" and "sher.legend" are both
+// "sher.legend", then validates it
+func Normalize(name string) (string, error) {
+	label, err := Label(name)
+	if err != nil {
+		return "", err
+	}
+	return label + Suffix, nil
+}
+
+// Label returns the normalized account name without the suffix, e.g. "sher" for "Sher.legend", as registered on L1
+func Label(name string) (string, error) {
+	label := strings.ToLower(strings.TrimSpace(name))
+	if i := strings.IndexByte(label, '.'); i >= 0 {
+		if label[i:] != Suffix {
+			return "", fmt.Errorf("%w: %q does not end with %q", ErrInvalidSuffix, name, Suffix)
+		}
+		label = label[:i]
+	}
+	if err := ValidateLabel(label); err != nil {
+		return "", err
+	}
+	return label, nil
+}
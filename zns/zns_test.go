@@ -0,0 +1,52 @@
+package zns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	for name, expected := range map[string]string{
+		"sher.legend":                 "sher",
+		"sher":                        "sher",
+		"Sher.Legend":                 "sher",
+		" GAVIN ":                     "gavin",
+		"a1":                          "a1",
+		"abcdefghij0123456789.legend": "abcdefghij0123456789",
+	} {
+		label, err := Label(name)
+		if assert.NoError(t, err, name) {
+			assert.Equal(t, expected, label, name)
+		}
+		normalized, err := Normalize(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, label+Suffix, normalized)
+		assert.NoError(t, Validate(normalized))
+	}
+
+	for name, expected := range map[string]error{
+		"":                        ErrInvalidLength,
+		".legend":                 ErrInvalidLength,
+		"abcdefghij01234567890":   ErrInvalidLength,
+		"sher_1.legend":           ErrInvalidCharacter,
+		"sh er":                   ErrInvalidCharacter,
+		"shér.legend":             ErrInvalidCharacter,
+		"sher.eth":                ErrInvalidSuffix,
+		"sub.sher.legend":         ErrInvalidSuffix,
+		"sher.legend.":            ErrInvalidSuffix,
+		"abcdefghij01234567890.x": ErrInvalidSuffix,
+	} {
+		_, err := Normalize(name)
+		assert.ErrorIs(t, err, expected, name)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate("sher.legend"))
+	assert.ErrorIs(t, Validate("Sher.legend"), ErrInvalidCharacter)
+	assert.ErrorIs(t, Validate("sher"), ErrInvalidSuffix)
+	assert.EqualError(t, Validate("sher-1.legend"), `invalid account name character: '-' at 4 of "sher-1", only lowercase letters and digits are allowed`)
+	assert.EqualError(t, ValidateLabel(""), `invalid account name length: "" has 0 characters, expected 1 to 20`)
+}